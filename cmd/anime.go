@@ -7,8 +7,10 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/bradleyyma/zutto/internal/mal"
+	"github.com/bradleyyma/zutto/internal/render"
 	"github.com/spf13/cobra"
 )
 
@@ -22,66 +24,155 @@ Available subcommands:
   search  - Search for anime by query
   ranking - Get anime rankings
   detail  - Get detailed information about an anime
+  season  - Browse anime airing in a season
 
 Examples:
   zutto anime search "one piece"
   zutto anime ranking --type tv
-  zutto anime detail --id 5114`,
+  zutto anime detail --id 5114
+  zutto anime season --year 2025 --season fall`,
 }
 
 // animeSearchCmd represents the anime search command
 var animeSearchCmd = &cobra.Command{
-	Use:   "search [query]",
-	Short: "Search for anime",
+	Use:         "search [query]",
+	Short:       "Search for anime",
+	Annotations: map[string]string{limitAnnotation: "search"},
 	Long: `Search for anime on MyAnimeList.
-	
+
+Results are shown as a table sized to the terminal. Use --columns to pick
+columns (rank, id, title, english, type, episodes, score, status).
+
+With --all or --max, results are fetched page by page (--limit per page)
+and each page is printed as its own table as soon as it arrives.
+
+With --local, a full-text index of every anime zutto has fetched, from
+searches, details, rankings, seasons and synced lists (see zutto sync), is
+searched instead, without the network. An anime's synopsis is indexed once
+its details have been fetched. The index covers titles, alternative
+titles, synonyms and synopses, takes queries of any length, has no upper
+--limit and understands:
+
+  frie*              words starting with "frie"
+  "steins gate"      words together, in order
+  title:, synopsis:  search only titles or only synopses
+  genre:, studio:    a genre or studio name containing the value
+  type:, status:, season:
+                     an exact media type, airing status or start season
+  year:, score:, episodes:
+                     a number, compared as in year:>2015, year:<=2015,
+                     year:2015 or year:2010..2015
+  -term              exclude matches of any of the above
+
 Examples:
   zutto anime search "one piece"
-  zutto anime search naruto --limit 20`,
+  zutto anime search naruto --limit 20
+  zutto anime search frieren --columns title,english,score
+  zutto anime search gundam --max 200
+  zutto anime search --local 'genre:mecha studio:sunrise year:>2015'
+  zutto anime search --local '"time travel" -type:movie' --all`,
 	Args: cobra.MinimumNArgs(1),
 	PreRunE: func(cmd *cobra.Command, args []string) error {
 		limit, _ := cmd.Flags().GetInt("limit")
-		if limit <= 0 || limit > 50 {
+		local, _ := cmd.Flags().GetBool("local")
+		if local && limit <= 0 {
+			return fmt.Errorf("limit must be greater than 0, got %d", limit)
+		}
+		if !local && (limit <= 0 || limit > 50) {
 			return fmt.Errorf("limit must be greater than 0 and less than or equal to 50, got %d", limit)
 		}
-		return nil
+		if err := validateAnimeTableColumns(); err != nil {
+			return err
+		}
+		return validatePaginationFlags(cmd)
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		query := strings.Join(args, " ")
 		limit, _ := cmd.Flags().GetInt("limit")
 
-		client := mal.NewClient(nil, "")
-		results, err := client.Anime.Search(query, limit)
+		client := newClient()
+		out := newRenderer()
+
+		local, _ := cmd.Flags().GetBool("local")
+		maxResults, paginate := paginationMax(cmd)
+		if (offline || local) && paginate {
+			// The mirror has no pages, so --all and --max cap one search
+			limit, paginate = maxResults, false
+		}
+		if paginate {
+			pages := newPagedAnimeTable(limit)
+			for anime, err := range client.Anime.SearchAllContext(cmd.Context(), query, limit, maxResults, animeTableFields...) {
+				if err != nil {
+					pages.flush()
+					fmt.Fprintf(os.Stderr, "Error searching for anime: %v\n", describeError(err))
+					os.Exit(1)
+				}
+				if !out.Text() {
+					renderItem(out, anime)
+					continue
+				}
+				pages.append(&anime.Node, 0)
+			}
+			if !out.Text() {
+				flushOutput(out)
+				return
+			}
+			pages.flush()
+			if pages.count == 0 {
+				fmt.Println("No anime found")
+				return
+			}
+			fmt.Printf("\nFound %d anime\n", pages.count)
+			return
+		}
+
+		var results *mal.AnimeSearchResponse
+		var err error
+		if local {
+			results, err = openMirror(true).Anime.QueryContext(cmd.Context(), query, limit)
+		} else {
+			results, err = animeSource(client).SearchContext(cmd.Context(), query, limit, animeTableFields...)
+		}
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error searching for anime: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error searching for anime: %v\n", describeError(err))
 			os.Exit(1)
 		}
-
-		if len(results.Data) == 0 {
-			fmt.Println("No anime found")
+		if !out.Text() {
+			renderItems(out, results.Data)
 			return
 		}
-
-		fmt.Printf("Found %d anime:\n\n", len(results.Data))
-		for i, anime := range results.Data {
-			fmt.Printf("%d. %s (ID: %d)\n", i+1, anime.Node.Title, anime.Node.ID)
-			if anime.Node.AlternativeTitles.En != "" {
-				fmt.Printf("   English: %s\n", anime.Node.AlternativeTitles.En)
-			}
+		table, _ := newAnimeTable()
+		for _, anime := range results.Data {
+			appendAnime(table, &anime.Node, 0)
+		}
+		if table.Len() == 0 {
+			fmt.Println("No anime found")
+			return
 		}
+		fmt.Printf("Found %d anime:\n\n", table.Len())
+		printAnimeTable(table)
 	},
 }
 
 // animeRankingCmd represents the anime ranking command
 var animeRankingCmd = &cobra.Command{
-	Use:   "ranking",
-	Short: "Get anime rankings",
+	Use:         "ranking",
+	Short:       "Get anime rankings",
+	Annotations: map[string]string{limitAnnotation: "ranking"},
 	Long: `Get anime rankings from MyAnimeList.
 
+Rankings are shown as a table sized to the terminal. Use --columns to pick
+columns (rank, id, title, english, type, episodes, score, status).
+
+With --all or --max, rankings are fetched page by page (--limit per page)
+and each page is printed as its own table as soon as it arrives.
+
 Examples:
   zutto anime ranking
   zutto anime ranking --type tv
-  zutto anime ranking --type movie --limit 20`,
+  zutto anime ranking --type movie --limit 20
+  zutto anime ranking --columns rank,title,score
+  zutto anime ranking --type tv --max 500`,
 	PreRunE: func(cmd *cobra.Command, args []string) error {
 		rankingType, _ := cmd.Flags().GetString("type")
 		if err := mal.ValidateAnimeRankingType(rankingType); err != nil {
@@ -92,32 +183,65 @@ Examples:
 		if limit <= 0 || limit > 100 {
 			return fmt.Errorf("limit must be between 1 and 100, got %d", limit)
 		}
-		return nil
+		if err := validateAnimeTableColumns(); err != nil {
+			return err
+		}
+		return validatePaginationFlags(cmd)
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		rankingType, _ := cmd.Flags().GetString("type")
 		limit, _ := cmd.Flags().GetInt("limit")
 		offset, _ := cmd.Flags().GetInt("offset")
 
-		client := mal.NewClient(nil, "")
-		rankings, err := client.Anime.Rankings(rankingType, limit, offset)
+		client := newClient()
+		out := newRenderer()
+
+		if maxResults, ok := paginationMax(cmd); ok {
+			pages := newPagedAnimeTable(limit)
+			for entry, err := range client.Anime.RankingsAllContext(cmd.Context(), rankingType, limit, offset, maxResults, animeTableFields...) {
+				if err != nil {
+					pages.flush()
+					fmt.Fprintf(os.Stderr, "Error retrieving anime rankings: %v\n", describeError(err))
+					os.Exit(1)
+				}
+				if !out.Text() {
+					renderItem(out, entry)
+					continue
+				}
+				pages.append(&entry.Node, entry.Ranking.Rank)
+			}
+			if !out.Text() {
+				flushOutput(out)
+				return
+			}
+			pages.flush()
+			if pages.count == 0 {
+				fmt.Println("No anime rankings found")
+				return
+			}
+			fmt.Printf("\nTop %d Anime Rankings (%s)\n", pages.count, rankingType)
+			return
+		}
+
+		rankings, err := client.Anime.RankingsContext(cmd.Context(), rankingType, limit, offset, animeTableFields...)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error retrieving anime rankings: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error retrieving anime rankings: %v\n", describeError(err))
 			os.Exit(1)
 		}
-
-		if len(rankings.Data) == 0 {
-			fmt.Println("No anime rankings found")
+		if !out.Text() {
+			renderItems(out, rankings.Data)
 			return
 		}
-
-		fmt.Printf("Top %d Anime Rankings (%s):\n\n", len(rankings.Data), rankingType)
+		table, _ := newAnimeTable()
 		for _, entry := range rankings.Data {
-			fmt.Printf("%d. %s (ID: %d)\n", entry.Ranking.Rank, entry.Node.Title, entry.Node.ID)
-			if entry.Node.AlternativeTitles.En != "" {
-				fmt.Printf("    English: %s\n", entry.Node.AlternativeTitles.En)
-			}
+			appendAnime(table, &entry.Node, entry.Ranking.Rank)
+		}
+		if table.Len() == 0 {
+			fmt.Println("No anime rankings found")
+			return
 		}
+		fmt.Printf("Top %d Anime Rankings (%s):\n\n", table.Len(), rankingType)
+		printAnimeTable(table)
 	},
 }
 
@@ -127,11 +251,17 @@ var animeDetailCmd = &cobra.Command{
 	Short: "Get detailed information about an anime",
 	Long: `Get detailed information about an anime on MyAnimeList by ID or name.
 
-You must provide either --id or --name (but not both).
+You must provide either --id or --name (but not both). A name is matched
+against titles, English and Japanese titles and synonyms; when several
+anime match about equally well you are asked to pick one, or shown the
+candidates if zutto is not running in a terminal. Use --fields to request
+and print specific sections instead of the default summary.
 
 Examples:
   zutto anime detail --id 5114
   zutto anime detail -i 5114
+  zutto anime detail --id 5114 --fields genres,studios,related_anime
+  zutto anime detail --id 5114 --fields 'related_anime{node{id,title}}'
   zutto anime detail --name "Fullmetal Alchemist: Brotherhood"
   zutto anime detail -n "naruto"`,
 	PreRunE: func(cmd *cobra.Command, args []string) error {
@@ -147,39 +277,56 @@ Examples:
 		if id < 0 {
 			return fmt.Errorf("id must be a positive integer, got %d", id)
 		}
-		return nil
+
+		fieldList, _ := cmd.Flags().GetString("fields")
+		return mal.ValidateAnimeFields(mal.SplitFields(fieldList))
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		id, _ := cmd.Flags().GetInt("id")
 		name, _ := cmd.Flags().GetString("name")
+		fieldList, _ := cmd.Flags().GetString("fields")
+		fields := mal.SplitFields(fieldList)
 
-		client := mal.NewClient(nil, "")
+		client := newClient()
+		out := newRenderer()
 
-		// If name is provided, search first to get the ID
+		// If name is provided, resolve it to an ID first
 		if name != "" {
-			fmt.Printf("Searching for anime: %s\n", name)
-			searchResults, err := client.Anime.Search(name, 1)
+			var err error
+			id, err = resolveAnimeName(cmd.Context(), animeSource(client), name)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error searching for anime: %v\n", err)
-				os.Exit(1)
-			}
-			if len(searchResults.Data) == 0 {
-				fmt.Fprintf(os.Stderr, "No anime found with name: %s\n", name)
+				fmt.Fprintf(os.Stderr, "Error finding anime: %v\n", describeError(err))
 				os.Exit(1)
 			}
-			id = searchResults.Data[0].Node.ID
-			fmt.Printf("Found: %s (ID: %d)\n\n", searchResults.Data[0].Node.Title, id)
 		}
 
-		// Get anime details
-		detail, err := client.Anime.Details(id)
+		// Get anime details, always including the titles for the header
+		request := []string{mal.DefaultAnimeDetailsFields, "alternative_titles"}
+		if len(fields) > 0 {
+			request = append([]string{"id", "title", "alternative_titles"}, fields...)
+		}
+		detail, err := animeSource(client).DetailsContext(cmd.Context(), id, request...)
+		if mal.IsNotFound(err) {
+			fmt.Fprintf(os.Stderr, "No anime with ID %d\n", id)
+			os.Exit(1)
+		}
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error getting anime details: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error getting anime details: %v\n", describeError(err))
 			os.Exit(1)
 		}
 
+		if !out.Text() {
+			renderValue(out, detail)
+			return
+		}
+
+		if len(fields) > 0 {
+			printAnimeSections(detail, fields)
+			return
+		}
+
 		// Display results
-		fmt.Printf("Title: %s\n", detail.Title)
+		fmt.Printf("Title: %s\n", detail.PreferredTitle(preferredLanguage()))
 		if detail.Synopsis != "" {
 			fmt.Printf("Synopsis: %s\n", detail.Synopsis)
 		}
@@ -197,6 +344,99 @@ Examples:
 	},
 }
 
+// animeSeasonCmd represents the anime season command
+var animeSeasonCmd = &cobra.Command{
+	Use:         "season",
+	Short:       "Browse anime airing in a season",
+	Annotations: map[string]string{limitAnnotation: "season"},
+	Long: `Browse anime that started airing in a season on MyAnimeList.
+
+Without --year and --season, the current season is used.
+
+Examples:
+  zutto anime season
+  zutto anime season --year 2025 --season fall
+  zutto anime season --sort anime_num_list_users --type tv,ona`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		season, _ := cmd.Flags().GetString("season")
+		if season != "" {
+			if err := mal.ValidateSeason(season); err != nil {
+				return err
+			}
+		}
+
+		year, _ := cmd.Flags().GetInt("year")
+		if year != 0 && (year < 1917 || year > time.Now().Year()+2) {
+			return fmt.Errorf("year must be between 1917 and %d, got %d", time.Now().Year()+2, year)
+		}
+
+		sort, _ := cmd.Flags().GetString("sort")
+		if err := mal.ValidateSeasonalSort(sort); err != nil {
+			return err
+		}
+
+		mediaTypes, _ := cmd.Flags().GetStringSlice("type")
+		if err := mal.ValidateAnimeMediaTypes(mediaTypes); err != nil {
+			return err
+		}
+
+		limit, _ := cmd.Flags().GetInt("limit")
+		if limit <= 0 || limit > 500 {
+			return fmt.Errorf("limit must be between 1 and 500, got %d", limit)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		year, _ := cmd.Flags().GetInt("year")
+		season, _ := cmd.Flags().GetString("season")
+		sort, _ := cmd.Flags().GetString("sort")
+		mediaTypes, _ := cmd.Flags().GetStringSlice("type")
+		limit, _ := cmd.Flags().GetInt("limit")
+		offset, _ := cmd.Flags().GetInt("offset")
+
+		current := mal.CurrentSeason(time.Now())
+		if year == 0 {
+			year = current.Year
+		}
+		if season == "" {
+			season = current.Season
+		}
+
+		client := newClient()
+		seasonal, err := client.Anime.SeasonalOfTypesContext(cmd.Context(), year, season, sort, limit, offset, mediaTypes,
+			"alternative_titles", "media_type", "mean", "num_episodes")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error retrieving seasonal anime: %v\n", describeError(err))
+			os.Exit(1)
+		}
+
+		out := newRenderer()
+		if !out.Text() {
+			renderItems(out, seasonal.Data)
+			return
+		}
+
+		if len(seasonal.Data) == 0 {
+			fmt.Printf("No anime found for %s %d\n", season, year)
+			return
+		}
+
+		fmt.Printf("%d Anime in %s %d:\n\n", len(seasonal.Data), season, year)
+		for i, anime := range seasonal.Data {
+			title := anime.Node.PreferredTitle(preferredLanguage())
+			fmt.Printf("%d. %s (ID: %d)\n", i+1, title, anime.Node.ID)
+			if anime.Node.AlternativeTitles.En != "" && anime.Node.AlternativeTitles.En != title {
+				fmt.Printf("   English: %s\n", anime.Node.AlternativeTitles.En)
+			}
+			fmt.Printf("   %s, %s episodes", anime.Node.MediaType, render.CountOrUnknown(anime.Node.NumEpisodes))
+			if anime.Node.Mean > 0 {
+				fmt.Printf(", score %.2f", anime.Node.Mean)
+			}
+			fmt.Println()
+		}
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(animeCmd)
 
@@ -204,16 +444,29 @@ func init() {
 	animeCmd.AddCommand(animeSearchCmd)
 	animeCmd.AddCommand(animeRankingCmd)
 	animeCmd.AddCommand(animeDetailCmd)
+	animeCmd.AddCommand(animeSeasonCmd)
 
 	// Search flags
-	animeSearchCmd.Flags().IntP("limit", "l", 10, "Maximum number of results to return (1-50)")
+	animeSearchCmd.Flags().IntP("limit", "l", 10, "Maximum number of results to return (1-50, no upper bound with --local), or page size with --all/--max")
+	animeSearchCmd.Flags().Bool("local", false, "Search the full-text index of every anime fetched so far")
+	addPaginationFlags(animeSearchCmd)
 
 	// Ranking flags
-	animeRankingCmd.Flags().String("type", "all", "Type of ranking (all, tv, movie, ova, ona, special, bypopularity, favorite)")
+	animeRankingCmd.Flags().String("type", "all", "Type of ranking (all, airing, upcoming, tv, movie, ova, ona, special, bypopularity, favorite)")
 	animeRankingCmd.Flags().IntP("limit", "l", 50, "Maximum number of results to return (1-100)")
 	animeRankingCmd.Flags().Int("offset", 0, "Offset for pagination")
+	addPaginationFlags(animeRankingCmd)
 
 	// Detail flags
 	animeDetailCmd.Flags().IntP("id", "i", 0, "Anime ID")
-	animeDetailCmd.Flags().StringP("name", "n", "", "Anime name (will search and use first result)")
+	animeDetailCmd.Flags().StringP("name", "n", "", "Anime name (the closest title match is used)")
+	animeDetailCmd.Flags().String("fields", "", "Comma-separated MAL fields to request and print (e.g. genres,studios{id,name},related_anime)")
+
+	// Season flags
+	animeSeasonCmd.Flags().Int("year", 0, "Year of the season (default current year)")
+	animeSeasonCmd.Flags().String("season", "", "Season (winter, spring, summer, fall; default current season)")
+	animeSeasonCmd.Flags().String("sort", "anime_score", "Sort order (anime_score, anime_num_list_users)")
+	animeSeasonCmd.Flags().StringSlice("type", nil, "Only show these media types (tv, movie, ova, ona, special, music)")
+	animeSeasonCmd.Flags().IntP("limit", "l", 100, "Maximum number of results to return (1-500)")
+	animeSeasonCmd.Flags().Int("offset", 0, "Offset for pagination")
 }
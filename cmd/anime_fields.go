@@ -0,0 +1,223 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+	"github.com/bradleyyma/zutto/internal/render"
+)
+
+// animeSections prints one section of an anime's details per MAL field.
+// Each printer is silent when its field is empty.
+var animeSections = map[string]func(d *mal.AnimeDetails){
+	"id": func(d *mal.AnimeDetails) {
+		fmt.Printf("ID: %d\n", d.ID)
+	},
+	"title": func(d *mal.AnimeDetails) {},
+	"main_picture": func(d *mal.AnimeDetails) {
+		if d.MainPicture.Large != "" || d.MainPicture.Medium != "" {
+			fmt.Printf("Picture: %s\n", firstNonEmpty(d.MainPicture.Large, d.MainPicture.Medium))
+		}
+	},
+	"alternative_titles": func(d *mal.AnimeDetails) {
+		if d.AlternativeTitles.En != "" {
+			fmt.Printf("English: %s\n", d.AlternativeTitles.En)
+		}
+		if d.AlternativeTitles.Ja != "" {
+			fmt.Printf("Japanese: %s\n", d.AlternativeTitles.Ja)
+		}
+		if d.AlternativeTitles.Synonyms != nil && len(*d.AlternativeTitles.Synonyms) > 0 {
+			fmt.Printf("Synonyms: %s\n", strings.Join(*d.AlternativeTitles.Synonyms, ", "))
+		}
+	},
+	"start_date": func(d *mal.AnimeDetails) {
+		if d.StartDate != "" {
+			fmt.Printf("Start Date: %s\n", d.StartDate)
+		}
+	},
+	"end_date": func(d *mal.AnimeDetails) {
+		if d.EndDate != "" {
+			fmt.Printf("End Date: %s\n", d.EndDate)
+		}
+	},
+	"synopsis": func(d *mal.AnimeDetails) {
+		if d.Synopsis != "" {
+			fmt.Printf("Synopsis: %s\n", d.Synopsis)
+		}
+	},
+	"mean": func(d *mal.AnimeDetails) {
+		if d.Mean > 0 {
+			fmt.Printf("Score: %.2f\n", d.Mean)
+		}
+	},
+	"rank": func(d *mal.AnimeDetails) {
+		if d.Rank > 0 {
+			fmt.Printf("Rank: #%d\n", d.Rank)
+		}
+	},
+	"popularity": func(d *mal.AnimeDetails) {
+		if d.Popularity > 0 {
+			fmt.Printf("Popularity: #%d\n", d.Popularity)
+		}
+	},
+	"num_list_users": func(d *mal.AnimeDetails) {
+		fmt.Printf("Members: %d\n", d.NumListUsers)
+	},
+	"num_scoring_users": func(d *mal.AnimeDetails) {
+		fmt.Printf("Scored by: %d\n", d.NumScoringUsers)
+	},
+	"nsfw": func(d *mal.AnimeDetails) {
+		if d.NSFW != "" {
+			fmt.Printf("NSFW: %s\n", d.NSFW)
+		}
+	},
+	"genres": func(d *mal.AnimeDetails) {
+		if len(d.Genres) > 0 {
+			names := make([]string, 0, len(d.Genres))
+			for _, g := range d.Genres {
+				names = append(names, g.Name)
+			}
+			fmt.Printf("Genres: %s\n", strings.Join(names, ", "))
+		}
+	},
+	"created_at": func(d *mal.AnimeDetails) {
+		if d.CreatedAt != "" {
+			fmt.Printf("Created: %s\n", d.CreatedAt)
+		}
+	},
+	"updated_at": func(d *mal.AnimeDetails) {
+		if d.UpdatedAt != "" {
+			fmt.Printf("Updated: %s\n", d.UpdatedAt)
+		}
+	},
+	"media_type": func(d *mal.AnimeDetails) {
+		if d.MediaType != "" {
+			fmt.Printf("Type: %s\n", d.MediaType)
+		}
+	},
+	"status": func(d *mal.AnimeDetails) {
+		if d.Status != "" {
+			fmt.Printf("Status: %s\n", d.Status)
+		}
+	},
+	"my_list_status": func(d *mal.AnimeDetails) {
+		if d.MyListStatus == nil {
+			fmt.Println("My List: not on list")
+			return
+		}
+		fmt.Printf("My List: %s, %s episodes", render.FormatStatus(d.MyListStatus.Status),
+			render.FormatProgress(d.MyListStatus.NumEpisodesWatched, d.NumEpisodes))
+		if d.MyListStatus.Score > 0 {
+			fmt.Printf(", score %d", d.MyListStatus.Score)
+		}
+		fmt.Println()
+	},
+	"num_episodes": func(d *mal.AnimeDetails) {
+		fmt.Printf("Number of Episodes: %d\n", d.NumEpisodes)
+	},
+	"start_season": func(d *mal.AnimeDetails) {
+		if d.StartSeason != nil {
+			fmt.Printf("Season: %s %d\n", d.StartSeason.Season, d.StartSeason.Year)
+		}
+	},
+	"broadcast": func(d *mal.AnimeDetails) {
+		if d.Broadcast != nil {
+			fmt.Printf("Broadcast: %s %s (JST)\n", d.Broadcast.DayOfTheWeek, d.Broadcast.StartTime)
+		}
+	},
+	"source": func(d *mal.AnimeDetails) {
+		if d.Source != "" {
+			fmt.Printf("Source: %s\n", render.FormatStatus(d.Source))
+		}
+	},
+	"average_episode_duration": func(d *mal.AnimeDetails) {
+		if d.AverageEpisodeDuration > 0 {
+			fmt.Printf("Episode Duration: %d min\n", d.AverageEpisodeDuration/60)
+		}
+	},
+	"rating": func(d *mal.AnimeDetails) {
+		if d.Rating != "" {
+			fmt.Printf("Rating: %s\n", d.Rating)
+		}
+	},
+	"studios": func(d *mal.AnimeDetails) {
+		if len(d.Studios) > 0 {
+			names := make([]string, 0, len(d.Studios))
+			for _, s := range d.Studios {
+				names = append(names, s.Name)
+			}
+			fmt.Printf("Studios: %s\n", strings.Join(names, ", "))
+		}
+	},
+	"pictures": func(d *mal.AnimeDetails) {
+		if len(d.Pictures) > 0 {
+			fmt.Println("Pictures:")
+			for _, p := range d.Pictures {
+				fmt.Printf("  %s\n", firstNonEmpty(p.Large, p.Medium))
+			}
+		}
+	},
+	"background": func(d *mal.AnimeDetails) {
+		if d.Background != "" {
+			fmt.Printf("Background: %s\n", d.Background)
+		}
+	},
+	"related_anime": func(d *mal.AnimeDetails) {
+		if len(d.RelatedAnime) > 0 {
+			fmt.Println("Related Anime:")
+			for _, r := range d.RelatedAnime {
+				fmt.Printf("  %s: %s (ID: %d)\n", r.RelationTypeFormatted, r.Node.PreferredTitle(preferredLanguage()), r.Node.ID)
+			}
+		}
+	},
+	"related_manga": func(d *mal.AnimeDetails) {
+		if len(d.RelatedManga) > 0 {
+			fmt.Println("Related Manga:")
+			for _, r := range d.RelatedManga {
+				fmt.Printf("  %s: %s (ID: %d)\n", r.RelationTypeFormatted, r.Node.PreferredTitle(preferredLanguage()), r.Node.ID)
+			}
+		}
+	},
+	"recommendations": func(d *mal.AnimeDetails) {
+		if len(d.Recommendations) > 0 {
+			fmt.Println("Recommendations:")
+			for _, r := range d.Recommendations {
+				fmt.Printf("  %s (ID: %d, recommended by %d)\n", r.Node.PreferredTitle(preferredLanguage()), r.Node.ID, r.NumRecommendations)
+			}
+		}
+	},
+	"statistics": func(d *mal.AnimeDetails) {
+		if d.Statistics != nil {
+			s := d.Statistics.Status
+			fmt.Println("Statistics:")
+			fmt.Printf("  Watching: %s\n  Completed: %s\n  On Hold: %s\n  Dropped: %s\n  Plan to Watch: %s\n",
+				s.Watching, s.Completed, s.OnHold, s.Dropped, s.PlanToWatch)
+			fmt.Printf("  Total: %d\n", d.Statistics.NumListUsers)
+		}
+	},
+}
+
+// printAnimeSections prints the title followed by the requested sections in
+// the order given
+func printAnimeSections(d *mal.AnimeDetails, fields []string) {
+	fmt.Printf("Title: %s\n", d.PreferredTitle(preferredLanguage()))
+	for _, field := range fields {
+		name, _, _ := strings.Cut(field, "{")
+		if printSection, ok := animeSections[name]; ok {
+			printSection(d)
+		}
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+	"github.com/bradleyyma/zutto/internal/render"
+)
+
+// animeTableFields are requested by commands that print an anime table
+var animeTableFields = []string{"alternative_titles", "media_type", "mean", "num_episodes", "rank", "status"}
+
+// newAnimeTable returns a table of anime for stdout, showing the columns
+// picked with --columns
+func newAnimeTable() (*render.Table, error) {
+	table := render.NewTable(render.TerminalWidth(os.Stdout), render.ColorEnabled(os.Stdout),
+		render.Column{Name: "rank", Header: "RANK", Align: render.AlignRight},
+		render.Column{Name: "id", Header: "ID", Align: render.AlignRight},
+		render.Column{Name: "title", Header: "TITLE", Flex: true, Color: func(string) string { return "1" }},
+		render.Column{Name: "english", Header: "ENGLISH", Flex: true},
+		render.Column{Name: "type", Header: "TYPE"},
+		render.Column{Name: "episodes", Header: "EPS", Align: render.AlignRight},
+		render.Column{Name: "score", Header: "SCORE", Align: render.AlignRight, Color: scoreColor},
+		render.Column{Name: "status", Header: "STATUS", Color: airingColor},
+	)
+	return table, table.Select(outputColumns)
+}
+
+// validateAnimeTableColumns checks --columns against the anime table when
+// text output is selected
+func validateAnimeTableColumns() error {
+	if outputFormat != string(render.Text) || outputTemplate != "" {
+		return nil
+	}
+	_, err := newAnimeTable()
+	return err
+}
+
+// appendAnime adds a row for anime to table. rank is the position to show,
+// or 0 for the anime's overall MAL rank.
+func appendAnime(table *render.Table, anime *mal.AnimeDetails, rank int) {
+	if rank == 0 {
+		rank = anime.Rank
+	}
+	var score string
+	if anime.Mean > 0 {
+		score = fmt.Sprintf("%.2f", anime.Mean)
+	}
+	table.Append(
+		numberOrBlank(rank),
+		strconv.Itoa(anime.ID),
+		anime.PreferredTitle(preferredLanguage()),
+		anime.AlternativeTitles.En,
+		anime.MediaType,
+		render.CountOrUnknown(anime.NumEpisodes),
+		score,
+		formatAiringStatus(anime.Status),
+	)
+}
+
+// pagedAnimeTable prints anime as they arrive from a paginated endpoint,
+// one table per page, so the first page shows before later ones are fetched
+type pagedAnimeTable struct {
+	table    *render.Table
+	pageSize int
+	// count is the number of anime appended so far
+	count int
+}
+
+func newPagedAnimeTable(pageSize int) *pagedAnimeTable {
+	table, _ := newAnimeTable()
+	return &pagedAnimeTable{table: table, pageSize: pageSize}
+}
+
+// append adds a row like appendAnime, printing the page once it is full
+func (p *pagedAnimeTable) append(anime *mal.AnimeDetails, rank int) {
+	appendAnime(p.table, anime, rank)
+	p.count++
+	if p.table.Len() >= p.pageSize {
+		p.flush()
+	}
+}
+
+// flush prints the rows of the current page, if any
+func (p *pagedAnimeTable) flush() {
+	if p.table.Len() == 0 {
+		return
+	}
+	if p.count > p.table.Len() {
+		fmt.Println()
+	}
+	printAnimeTable(p.table)
+	p.table, _ = newAnimeTable()
+}
+
+// printAnimeTable writes table to stdout
+func printAnimeTable(table *render.Table) {
+	if err := table.Write(os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func numberOrBlank(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// formatAiringStatus shortens MAL's airing status for narrow columns
+func formatAiringStatus(status string) string {
+	switch status {
+	case "finished_airing":
+		return "finished"
+	case "currently_airing":
+		return "airing"
+	case "not_yet_aired":
+		return "upcoming"
+	}
+	return status
+}
+
+func scoreColor(score string) string {
+	value, err := strconv.ParseFloat(score, 64)
+	switch {
+	case err != nil:
+		return ""
+	case value >= 8:
+		return "32"
+	case value >= 7:
+		return "33"
+	}
+	return ""
+}
+
+func airingColor(status string) string {
+	switch status {
+	case "airing":
+		return "32"
+	case "upcoming":
+		return "36"
+	}
+	return ""
+}
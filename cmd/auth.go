@@ -0,0 +1,241 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+	"github.com/spf13/cobra"
+)
+
+// authCmd represents the auth command
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Log in to MyAnimeList",
+	Long: `Manage the MyAnimeList login used for list and profile commands.
+
+Available subcommands:
+  login  - Authorize zutto with your MyAnimeList account
+  status - Show the current login state
+  logout - Remove the stored token
+
+The client ID is read from MAL_CLIENT_ID or the client_id config setting,
+and the optional client secret from MAL_CLIENT_SECRET. Each config profile
+keeps its own login. The redirect URL registered for your MAL app must
+match --redirect-url (default ` + mal.DefaultRedirectURL + `).
+
+Examples:
+  zutto auth login
+  zutto auth status
+  zutto auth logout`,
+}
+
+// authLoginCmd represents the auth login command
+var authLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Authorize zutto with your MyAnimeList account",
+	Long: `Run the MyAnimeList OAuth2 login flow.
+
+zutto opens the authorization page in your browser and listens on the
+redirect URL for MAL to send you back. The resulting token is stored in
+your config directory and refreshed automatically.
+
+Examples:
+  zutto auth login
+  zutto auth login --no-browser
+  zutto auth login --redirect-url http://localhost:9000/callback`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if settings.ClientID == "" {
+			return fmt.Errorf("a client ID is required to log in: set MAL_CLIENT_ID or run `zutto config set client_id <id>`")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		redirectURL, _ := cmd.Flags().GetString("redirect-url")
+		noBrowser, _ := cmd.Flags().GetBool("no-browser")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+
+		auth, err := newAuthenticator()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		auth.Config.RedirectURL = redirectURL
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel()
+
+		_, err = auth.Login(ctx, func(authURL string) error {
+			fmt.Printf("Open this URL to authorize zutto:\n\n  %s\n\n", authURL)
+			if !noBrowser {
+				if err := openBrowser(authURL); err != nil {
+					fmt.Fprintf(os.Stderr, "Could not open a browser: %v\n", err)
+				}
+			}
+			fmt.Println("Waiting for authorization...")
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error logging in: %v\n", describeError(err))
+			os.Exit(1)
+		}
+
+		client := newClient()
+		if user, err := client.User.MeContext(cmd.Context()); err == nil {
+			fmt.Printf("Logged in as %s\n", user.Name)
+		} else {
+			fmt.Println("Logged in")
+		}
+	},
+}
+
+// authStatusCmd represents the auth status command
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the current login state",
+	Run: func(cmd *cobra.Command, args []string) {
+		store, err := tokenStore()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		out := newRenderer()
+		token, err := store.Load()
+		if errors.Is(err, mal.ErrNotLoggedIn) {
+			if !out.Text() {
+				renderValue(out, authStatus{TokenFile: store.Path})
+				return
+			}
+			fmt.Println("Not logged in")
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading token: %v\n", err)
+			os.Exit(1)
+		}
+
+		if out.Text() {
+			fmt.Printf("Token file: %s\n", store.Path)
+			if !token.Expiry.IsZero() {
+				state := "valid until"
+				if token.Expiry.Before(time.Now()) {
+					state = "expired at"
+				}
+				fmt.Printf("Access token: %s %s\n", state, token.Expiry.Local().Format(time.RFC1123))
+			}
+			fmt.Printf("Refresh token: %t\n", token.RefreshToken != "")
+		}
+
+		client := newClient()
+		user, err := client.User.MeContext(cmd.Context())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching profile: %v\n", describeError(err))
+			os.Exit(1)
+		}
+
+		if !out.Text() {
+			status := authStatus{
+				LoggedIn:        true,
+				TokenFile:       store.Path,
+				HasRefreshToken: token.RefreshToken != "",
+				User:            user,
+			}
+			if !token.Expiry.IsZero() {
+				status.Expiry = &token.Expiry
+			}
+			renderValue(out, status)
+			return
+		}
+
+		fmt.Printf("Logged in as %s (ID: %d)\n", user.Name, user.ID)
+	},
+}
+
+// authStatus is the structured form of auth status
+type authStatus struct {
+	LoggedIn        bool       `json:"logged_in"`
+	TokenFile       string     `json:"token_file"`
+	Expiry          *time.Time `json:"expiry,omitempty"`
+	HasRefreshToken bool       `json:"has_refresh_token"`
+	User            *mal.User  `json:"user,omitempty"`
+}
+
+// authLogoutCmd represents the auth logout command
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Remove the stored token",
+	Run: func(cmd *cobra.Command, args []string) {
+		store, err := tokenStore()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := store.Delete(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error logging out: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("Logged out")
+	},
+}
+
+// newAuthenticator returns an Authenticator backed by the active profile's
+// token file
+func newAuthenticator() (*mal.Authenticator, error) {
+	store, err := tokenStore()
+	if err != nil {
+		return nil, err
+	}
+	return mal.NewAuthenticator(settings.ClientID, "", store), nil
+}
+
+// tokenStore returns the default token file, or a separate file per
+// config profile so profiles can log in to different accounts
+func tokenStore() (*mal.FileTokenStore, error) {
+	store, err := mal.DefaultTokenStore()
+	if err != nil {
+		return nil, err
+	}
+	if profile := activeProfile(); profile != "" {
+		store.Path = filepath.Join(filepath.Dir(store.Path), "token-"+profile+".json")
+	}
+	return store, nil
+}
+
+// openBrowser tries to open url with the platform's default handler
+func openBrowser(url string) error {
+	var name string
+	var args []string
+	switch runtime.GOOS {
+	case "darwin":
+		name = "open"
+	case "windows":
+		name, args = "rundll32", []string{"url.dll,FileProtocolHandler"}
+	default:
+		name = "xdg-open"
+	}
+	return exec.Command(name, append(args, url)...).Start()
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+
+	// Add subcommands
+	authCmd.AddCommand(authLoginCmd)
+	authCmd.AddCommand(authStatusCmd)
+	authCmd.AddCommand(authLogoutCmd)
+
+	// Login flags
+	authLoginCmd.Flags().String("redirect-url", mal.DefaultRedirectURL, "Loopback redirect URL registered for your MAL app")
+	authLoginCmd.Flags().Bool("no-browser", false, "Print the authorization URL without opening a browser")
+	authLoginCmd.Flags().Duration("timeout", 5*time.Minute, "How long to wait for authorization")
+}
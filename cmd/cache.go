@@ -0,0 +1,115 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+	"github.com/spf13/cobra"
+)
+
+// cacheCmd represents the cache command
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and clear the response cache",
+	Long: `Inspect and clear the on-disk cache of MyAnimeList responses.
+
+Anime and manga details are cached for a day, seasonal charts for six
+hours, and search results and rankings for an hour. Your own list is never cached. Use --refresh on any command to
+fetch fresh data, or --no-cache to bypass the cache entirely.
+
+Available subcommands:
+  stats - Show the number and size of cached responses
+  clear - Remove cached responses
+
+Examples:
+  zutto cache stats
+  zutto cache clear
+  zutto cache clear --expired`,
+}
+
+// cacheStatsCmd represents the cache stats command
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show the number and size of cached responses",
+	Run: func(cmd *cobra.Command, args []string) {
+		cache, err := mal.DefaultDiskCache()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		stats, err := cache.Stats()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading cache: %v\n", err)
+			os.Exit(1)
+		}
+
+		out := newRenderer()
+		if !out.Text() {
+			renderValue(out, stats)
+			return
+		}
+
+		fmt.Printf("Location: %s\n", cache.Dir)
+		fmt.Printf("Entries: %d (%d expired)\n", stats.Entries, stats.Expired)
+		fmt.Printf("Size: %s\n", formatBytes(stats.Bytes))
+	},
+}
+
+// cacheClearCmd represents the cache clear command
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove cached responses",
+	Run: func(cmd *cobra.Command, args []string) {
+		expiredOnly, _ := cmd.Flags().GetBool("expired")
+
+		cache, err := mal.DefaultDiskCache()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		removed, err := cache.Clear(expiredOnly)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error clearing cache: %v\n", err)
+			os.Exit(1)
+		}
+		out := newRenderer()
+		if !out.Text() {
+			renderValue(out, struct {
+				Removed int `json:"removed"`
+			}{removed})
+			return
+		}
+		fmt.Printf("Removed %d cached responses\n", removed)
+	},
+}
+
+// formatBytes renders a byte count using binary units
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+
+	// Add subcommands
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+
+	// Clear flags
+	cacheClearCmd.Flags().Bool("expired", false, "Only remove expired responses")
+}
@@ -0,0 +1,199 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bradleyyma/zutto/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and change zutto settings",
+	Long: `View and change settings in the zutto config file.
+
+With --profile, get and set read and write that profile instead of the
+top-level settings; list shows the values the profile ends up with.
+
+Available subcommands:
+  get  - Print the value of a setting
+  set  - Change a setting
+  list - Show every setting and where its value comes from
+  path - Print the location of the config file
+
+Examples:
+  zutto config set client_id 0123456789abcdef
+  zutto config set output json --profile work
+  zutto config get limits.search
+  zutto config list`,
+	// Settings are not resolved for config commands so a bad value in the
+	// file can still be fixed with config set
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return validateOutputFlags()
+	},
+}
+
+// configGetCmd represents the config get command
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the value of a setting",
+	Long: `Print the effective value of a setting, taking environment variables
+and the selected profile into account.
+
+Examples:
+  zutto config get client_id
+  zutto config get output --profile work`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		file := loadConfigFile()
+		value, _, err := file.Lookup(activeProfile(), args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if value == "" {
+			fmt.Fprintf(os.Stderr, "%s is not set\n", args[0])
+			os.Exit(1)
+		}
+		fmt.Println(value)
+	},
+}
+
+// configSetCmd represents the config set command
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting",
+	Long: `Change a setting in the config file. An empty value removes it.
+
+Examples:
+  zutto config set client_id 0123456789abcdef
+  zutto config set limits.search 25
+  zutto config set cache.search_ttl 30m
+  zutto config set nsfw true --profile work
+  zutto config set output ""`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		key, value := args[0], args[1]
+		path, err := configPath()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		file := loadConfigFile()
+
+		profile := activeProfile()
+		if err := file.Set(profile, key, value); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := file.Save(path); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		target := ""
+		if profile != "" {
+			target = fmt.Sprintf(" in profile %s", profile)
+		}
+		if value == "" {
+			fmt.Printf("Removed %s%s\n", key, target)
+		} else {
+			fmt.Printf("Set %s to %s%s\n", key, value, target)
+		}
+	},
+}
+
+// configSetting is the structured form of one config list entry
+type configSetting struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Source      string `json:"source"`
+	Description string `json:"description"`
+}
+
+// configListCmd represents the config list command
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show every setting and where its value comes from",
+	Run: func(cmd *cobra.Command, args []string) {
+		file := loadConfigFile()
+		profile := activeProfile()
+
+		var entries []configSetting
+		for _, key := range config.Keys {
+			value, source, err := file.Lookup(profile, key.Name)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			entries = append(entries, configSetting{
+				Key:         key.Name,
+				Value:       value,
+				Source:      source,
+				Description: key.Description,
+			})
+		}
+
+		out := newRenderer()
+		if !out.Text() {
+			renderItems(out, entries)
+			return
+		}
+
+		for _, entry := range entries {
+			if entry.Value == "" {
+				fmt.Printf("%s = (unset)\n", entry.Key)
+				continue
+			}
+			fmt.Printf("%s = %s  (%s)\n", entry.Key, entry.Value, entry.Source)
+		}
+		if names := file.ProfileNames(); len(names) > 0 {
+			fmt.Printf("\nProfiles: %s\n", strings.Join(names, ", "))
+		}
+	},
+}
+
+// configPathCmd represents the config path command
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the location of the config file",
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := configPath()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(path)
+	},
+}
+
+// loadConfigFile reads the selected config file or exits
+func loadConfigFile() *config.File {
+	path, err := configPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	file, err := config.Load(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return file
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+
+	// Add subcommands
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configPathCmd)
+}
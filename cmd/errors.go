@@ -0,0 +1,36 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+	"github.com/bradleyyma/zutto/internal/mirror"
+)
+
+// describeError turns common MAL API failures into messages a user can act
+// on. Other errors are returned as-is.
+func describeError(err error) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "request cancelled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "request timed out (see --request-timeout)"
+	case errors.Is(err, errOffline):
+		return "this needs MyAnimeList, run it without --offline"
+	case errors.Is(err, mirror.ErrNotSynced):
+		return err.Error() + ": run `zutto sync` to mirror it"
+	case errors.Is(err, mal.ErrNotLoggedIn):
+		return "this command requires logging in: run `zutto auth login`"
+	case mal.IsUnauthorized(err):
+		return "MyAnimeList rejected the credentials: check MAL_CLIENT_ID, or run `zutto auth login` again"
+	case mal.IsForbidden(err):
+		return "MyAnimeList denied access to this resource"
+	case mal.IsRateLimited(err):
+		return "MyAnimeList is rate limiting requests, try again in a moment"
+	}
+	return err.Error()
+}
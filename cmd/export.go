@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bradleyyma/zutto/internal/backup"
+	"github.com/spf13/cobra"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Back up your anime or manga list to a file",
+	Long: `Export your whole anime or manga list, including scores, progress, dates,
+tags and comments.
+
+Formats:
+  mal-xml - MyAnimeList's own XML export format, which MyAnimeList and most
+            other trackers can import
+  csv     - One row per entry with a header row
+  json    - The list as JSON
+
+The list is written to stdout unless --file is given. Exporting requires
+"zutto auth login".
+
+Examples:
+  zutto export -o animelist.xml
+  zutto export --type manga -o mangalist.xml
+  zutto export --format csv -o animelist.csv
+  zutto export --format json | jq '.entries | length'`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		if err := backup.ValidateFormat(format); err != nil {
+			return err
+		}
+		listType, _ := cmd.Flags().GetString("type")
+		return backup.ValidateListType(listType)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		listType, _ := cmd.Flags().GetString("type")
+		file, _ := cmd.Flags().GetString("file")
+
+		client := newClient()
+		list, err := backup.Fetch(cmd.Context(), client, backup.ListType(listType))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error retrieving %s list: %v\n", listType, describeError(err))
+			os.Exit(1)
+		}
+
+		if file == "" || file == "-" {
+			if err := backup.Write(os.Stdout, list, backup.Format(format)); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		f, err := os.Create(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		err = backup.Write(f, list, backup.Format(format))
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", file, err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Exported %d %s entries to %s\n", len(list.Entries), listType, file)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	formats := make([]string, len(backup.Formats))
+	for i, f := range backup.Formats {
+		formats[i] = string(f)
+	}
+	exportCmd.Flags().String("format", string(backup.MALXML), "File format ("+strings.Join(formats, ", ")+")")
+	exportCmd.Flags().StringP("type", "t", string(backup.Anime), "List to export (anime, manga)")
+	exportCmd.Flags().StringP("file", "o", "", "File to write (default stdout)")
+}
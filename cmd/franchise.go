@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+	"github.com/bradleyyma/zutto/internal/render"
+	"github.com/spf13/cobra"
+)
+
+// graphFormats are the values accepted by --graph
+var graphFormats = []string{"dot", "mermaid"}
+
+// animeFranchiseCmd represents the anime franchise command
+var animeFranchiseCmd = &cobra.Command{
+	Use:   "franchise <anime-id|name>",
+	Short: "Show a franchise's watch order and relation graph",
+	Long: `Find every anime connected to an anime through sequel, prequel, side
+story and alternative version or setting relations, and print them in
+watch order: each anime after its prequels, and side stories after the
+story they belong to. Anime that are otherwise unordered follow release
+dates.
+
+Relations are followed breadth-first up to --depth hops away; each anime is
+fetched once. Use --graph to print the relation graph instead, as Graphviz
+DOT or a Mermaid flowchart. Sequels are drawn as solid arrows and other
+relations as dashed ones.
+
+Examples:
+  zutto anime franchise 5081
+  zutto anime franchise "monogatari"
+  zutto anime franchise 356 --depth 3
+  zutto anime franchise 5081 --graph dot | dot -Tsvg > monogatari.svg
+  zutto anime franchise 5081 --graph mermaid`,
+	Args: cobra.MinimumNArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		depth, _ := cmd.Flags().GetInt("depth")
+		if depth < 1 {
+			return fmt.Errorf("--depth must be at least 1, got %d", depth)
+		}
+		graph, _ := cmd.Flags().GetString("graph")
+		if graph != "" && !slices.Contains(graphFormats, graph) {
+			return fmt.Errorf("invalid --graph %q, must be one of: %s", graph, strings.Join(graphFormats, ", "))
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		depth, _ := cmd.Flags().GetInt("depth")
+		graph, _ := cmd.Flags().GetString("graph")
+
+		client := newClient()
+
+		query := strings.Join(args, " ")
+		id, err := strconv.Atoi(query)
+		if err != nil || id <= 0 {
+			id, err = resolveAnimeName(cmd.Context(), client.Anime, query)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error finding anime: %v\n", describeError(err))
+				os.Exit(1)
+			}
+		}
+
+		franchise, err := client.Anime.FranchiseContext(cmd.Context(), id, mal.FranchiseOptions{MaxDepth: depth})
+		if mal.IsNotFound(err) {
+			fmt.Fprintf(os.Stderr, "No anime with ID %d\n", id)
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting franchise: %v\n", describeError(err))
+			os.Exit(1)
+		}
+		for _, e := range franchise.Errors {
+			fmt.Fprintf(os.Stderr, "Warning: skipped anime %d: %v\n", e.ID, describeError(e.Err))
+		}
+		if franchise.Truncated {
+			fmt.Fprintf(os.Stderr, "Warning: stopped %d relations away; use --depth to follow more\n", depth)
+		}
+
+		switch graph {
+		case "dot":
+			err = franchise.WriteDOT(os.Stdout, preferredLanguage())
+		case "mermaid":
+			err = franchise.WriteMermaid(os.Stdout, preferredLanguage())
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			os.Exit(1)
+		}
+		if graph != "" {
+			return
+		}
+
+		order := franchise.WatchOrder()
+
+		out := newRenderer()
+		if !out.Text() {
+			output := struct {
+				*mal.Franchise
+				WatchOrder []int `json:"watch_order"`
+			}{Franchise: franchise}
+			for _, entry := range order {
+				output.WatchOrder = append(output.WatchOrder, entry.Anime.ID)
+			}
+			renderValue(out, output)
+			return
+		}
+
+		root := franchise.Entries[0].Anime
+		fmt.Printf("Watch order for %s (%d anime):\n\n", root.PreferredTitle(preferredLanguage()), len(order))
+		for i, entry := range order {
+			anime := entry.Anime
+			fmt.Printf("%2d. %s (ID: %d)\n", i+1, anime.PreferredTitle(preferredLanguage()), anime.ID)
+
+			var details []string
+			if anime.MediaType != "" {
+				details = append(details, anime.MediaType)
+			}
+			details = append(details, render.CountOrUnknown(anime.NumEpisodes)+" episodes")
+			if anime.StartDate != "" {
+				details = append(details, "aired "+anime.StartDate)
+			}
+			fmt.Printf("    %s\n", strings.Join(details, ", "))
+		}
+	},
+}
+
+func init() {
+	animeCmd.AddCommand(animeFranchiseCmd)
+
+	animeFranchiseCmd.Flags().Int("depth", mal.DefaultFranchiseDepth, "Maximum number of relations to follow from the anime")
+	animeFranchiseCmd.Flags().String("graph", "", "Print the relation graph instead (dot, mermaid)")
+}
@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bradleyyma/zutto/internal/backup"
+	"github.com/bradleyyma/zutto/internal/mal"
+	"github.com/spf13/cobra"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import an anime or manga list from a file",
+	Long: `Import an anime or manga list from a MyAnimeList XML export (compressed or
+not), or from a CSV or JSON file written by "zutto export".
+
+The file is compared against your list and the differences are printed
+per entry before anything is changed. Use --dry-run to only print them.
+Entries missing from the file are never removed from your list.
+
+Strategies for entries already on your list:
+  overwrite             - Replace them with the imported entries
+  keep-higher-progress  - Import them, but keep your list's status and
+                          progress where they are further along
+  skip-existing         - Leave them alone and only add new entries
+
+Updates are sent one at a time at --rate per second. Applied entries are
+recorded in a journal in the cache directory, so if the import stops
+part way, running the same command again continues where it stopped.
+Importing requires "zutto auth login".
+
+Examples:
+  zutto import animelist.xml.gz --dry-run
+  zutto import animelist.xml --strategy keep-higher-progress
+  zutto import mangalist.csv --strategy skip-existing`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if format, _ := cmd.Flags().GetString("format"); format != "" {
+			if err := backup.ValidateFormat(format); err != nil {
+				return err
+			}
+		}
+		if rate, _ := cmd.Flags().GetFloat64("rate"); rate <= 0 {
+			return fmt.Errorf("rate must be positive, got %g", rate)
+		}
+		strategy, _ := cmd.Flags().GetString("strategy")
+		return backup.ValidateStrategy(strategy)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		path := args[0]
+		format, _ := cmd.Flags().GetString("format")
+		strategyName, _ := cmd.Flags().GetString("strategy")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		restart, _ := cmd.Flags().GetBool("restart")
+		rate, _ := cmd.Flags().GetFloat64("rate")
+		strategy := backup.Strategy(strategyName)
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if format == "" {
+			format = string(backup.DetectFormat(path))
+		}
+		imported, err := backup.Read(bytes.NewReader(data), backup.Format(format))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", path, err)
+			os.Exit(1)
+		}
+
+		client := newClient()
+		client.SetRateLimit(rate, 1)
+		current, err := backup.Fetch(cmd.Context(), client, imported.Type)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error retrieving %s list: %v\n", imported.Type, describeError(err))
+			os.Exit(1)
+		}
+		changes := backup.Plan(imported, current, strategy)
+
+		out := newRenderer()
+		if out.Text() {
+			printImportPlan(imported.Type, changes)
+		} else {
+			renderItems(out, changes)
+		}
+		if dryRun {
+			return
+		}
+
+		journalPath, err := backup.JournalPath(data, strategy)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if restart {
+			if err := os.Remove(journalPath); err != nil && !os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, "Error removing journal: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		journal, err := backup.OpenJournal(journalPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if journal.Len() > 0 {
+			fmt.Fprintf(os.Stderr, "Resuming import: %s already applied\n", entryCount(journal.Len()))
+		}
+
+		applied, failed := applyImport(cmd.Context(), client, journal, imported.Type, changes)
+
+		if failed > 0 || cmd.Context().Err() != nil {
+			journal.Close()
+			fmt.Fprintf(os.Stderr, "Imported %s, %d failed or not attempted. Run the same command again to continue.\n",
+				entryCount(applied), failed)
+			os.Exit(1)
+		}
+		if err := journal.Remove(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		fmt.Fprintf(os.Stderr, "Imported %s\n", entryCount(applied))
+	},
+}
+
+// applyImport applies the add and update changes not yet in journal,
+// recording each one that succeeds. A failed entry is reported and
+// skipped; cancelling ctx stops the import and counts the remaining
+// entries as failed.
+func applyImport(ctx context.Context, client *mal.Client, journal *backup.Journal, t backup.ListType, changes []backup.Change) (applied, failed int) {
+	var pending []backup.Change
+	for _, c := range changes {
+		if (c.Action == backup.Add || c.Action == backup.Update) && !journal.Done(c.Entry.ID) {
+			pending = append(pending, c)
+		}
+	}
+
+	for i, c := range pending {
+		if ctx.Err() != nil {
+			return applied, failed + len(pending) - i
+		}
+		name := entryName(t, c.Entry)
+		if err := backup.Apply(ctx, client, t, c); err != nil {
+			if errors.Is(err, context.Canceled) {
+				return applied, failed + len(pending) - i
+			}
+			fmt.Fprintf(os.Stderr, "[%d/%d] Failed to import %s: %v\n", i+1, len(pending), name, describeError(err))
+			failed++
+			continue
+		}
+		if err := journal.Record(c.Entry.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		applied++
+		verb := "Added"
+		if c.Action == backup.Update {
+			verb = "Updated"
+		}
+		fmt.Fprintf(os.Stderr, "[%d/%d] %s %s\n", i+1, len(pending), verb, name)
+	}
+	return applied, failed
+}
+
+// printImportPlan prints what an import changes, entry by entry, followed
+// by a summary. Unchanged entries are only counted.
+func printImportPlan(t backup.ListType, changes []backup.Change) {
+	counts := map[backup.Action]int{}
+	for _, c := range changes {
+		counts[c.Action]++
+		name := entryName(t, c.Entry)
+		switch c.Action {
+		case backup.Add:
+			fmt.Printf("+ %s\n", name)
+			for _, f := range c.Fields {
+				fmt.Printf("    %s: %s\n", fieldLabel(t, f.Field), displayValue(f.New))
+			}
+		case backup.Update:
+			fmt.Printf("~ %s\n", name)
+			for _, f := range c.Fields {
+				fmt.Printf("    %s: %s -> %s\n", fieldLabel(t, f.Field), displayValue(f.Old), displayValue(f.New))
+			}
+		case backup.Skip:
+			fmt.Printf("= %s: already on your list, skipped\n", name)
+		}
+	}
+
+	fmt.Printf("\n%d to add, %d to update, %d skipped, %d unchanged\n",
+		counts[backup.Add], counts[backup.Update], counts[backup.Skip], counts[backup.Unchanged])
+}
+
+// entryName names an entry as "Title (id)", or "anime id" when the file
+// has no title
+func entryName(t backup.ListType, e backup.Entry) string {
+	if e.Title == "" {
+		return fmt.Sprintf("%s %d", t, e.ID)
+	}
+	return fmt.Sprintf("%s (%d)", e.Title, e.ID)
+}
+
+// fieldLabel names an import field for display
+func fieldLabel(t backup.ListType, field string) string {
+	switch field {
+	case "progress":
+		if t == backup.Manga {
+			return "chapters read"
+		}
+		return "episodes watched"
+	case "repeating":
+		if t == backup.Manga {
+			return "rereading"
+		}
+		return "rewatching"
+	case "times_repeated":
+		if t == backup.Manga {
+			return "times reread"
+		}
+		return "times rewatched"
+	}
+	return strings.ReplaceAll(field, "_", " ")
+}
+
+func entryCount(n int) string {
+	if n == 1 {
+		return "1 entry"
+	}
+	return fmt.Sprintf("%d entries", n)
+}
+
+func displayValue(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	strategies := make([]string, len(backup.Strategies))
+	for i, s := range backup.Strategies {
+		strategies[i] = string(s)
+	}
+	importCmd.Flags().String("format", "", "File format (mal-xml, csv, json); detected from the file name by default")
+	importCmd.Flags().String("strategy", string(backup.Overwrite), "How to import entries already on your list ("+strings.Join(strategies, ", ")+")")
+	importCmd.Flags().Bool("dry-run", false, "Print the changes without applying them")
+	importCmd.Flags().Float64("rate", 1, "Maximum list updates per second")
+	importCmd.Flags().Bool("restart", false, "Ignore the journal of an interrupted import and start over")
+}
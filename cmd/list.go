@@ -0,0 +1,286 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+	"github.com/bradleyyma/zutto/internal/render"
+	"github.com/spf13/cobra"
+)
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "View and edit your anime list",
+	Long: `View and edit your anime list on MyAnimeList.
+
+Changing your list requires logging in with "zutto auth login".
+
+Available subcommands:
+  show   - Show an anime list
+  set    - Add an anime to your list or update its entry
+  remove - Remove an anime from your list
+
+Examples:
+  zutto list show --status watching --sort list_updated_at
+  zutto list set 5114 --status completed --score 9 --episodes 64
+  zutto list remove 5114`,
+}
+
+// listShowCmd represents the list show command
+var listShowCmd = &cobra.Command{
+	Use:         "show",
+	Short:       "Show an anime list",
+	Annotations: map[string]string{limitAnnotation: "list"},
+	Long: `Show your anime list, or another user's public list.
+
+Examples:
+  zutto list show
+  zutto list show --status watching --sort list_updated_at
+  zutto list show --user someone --status completed --sort list_score`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		status, _ := cmd.Flags().GetString("status")
+		if status != "" {
+			if err := mal.ValidateAnimeListStatus(status); err != nil {
+				return err
+			}
+		}
+
+		sort, _ := cmd.Flags().GetString("sort")
+		if sort != "" {
+			if err := mal.ValidateAnimeListSort(sort); err != nil {
+				return err
+			}
+		}
+
+		limit, _ := cmd.Flags().GetInt("limit")
+		if limit <= 0 || limit > 1000 {
+			return fmt.Errorf("limit must be between 1 and 1000, got %d", limit)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		user, _ := cmd.Flags().GetString("user")
+		status, _ := cmd.Flags().GetString("status")
+		sort, _ := cmd.Flags().GetString("sort")
+		limit, _ := cmd.Flags().GetInt("limit")
+		offset, _ := cmd.Flags().GetInt("offset")
+
+		client := newClient()
+		list, err := listSource(client).AnimeListContext(cmd.Context(), user, mal.AnimeListOptions{
+			Status: status,
+			Sort:   sort,
+			Limit:  limit,
+			Offset: offset,
+		})
+		if mal.IsNotFound(err) {
+			fmt.Fprintf(os.Stderr, "No user named %s\n", user)
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error retrieving anime list: %v\n", describeError(err))
+			os.Exit(1)
+		}
+
+		out := newRenderer()
+		if !out.Text() {
+			renderItems(out, list.Data)
+			return
+		}
+
+		if len(list.Data) == 0 {
+			fmt.Println("No anime found on list")
+			return
+		}
+
+		for _, entry := range list.Data {
+			fmt.Printf("%s (ID: %d)\n", entry.Node.PreferredTitle(preferredLanguage()), entry.Node.ID)
+			fmt.Printf("   %s  %s episodes", render.FormatStatus(entry.ListStatus.Status),
+				render.FormatProgress(entry.ListStatus.NumEpisodesWatched, entry.Node.NumEpisodes))
+			if entry.ListStatus.Score > 0 {
+				fmt.Printf("  score %d", entry.ListStatus.Score)
+			}
+			fmt.Println()
+		}
+	},
+}
+
+// listSetCmd represents the list set command
+var listSetCmd = &cobra.Command{
+	Use:     "set <anime-id>",
+	Aliases: []string{"add", "update"},
+	Short:   "Add an anime to your list or update its entry",
+	Long: `Add an anime to your list or update its entry. Only the flags you pass
+are changed.
+
+Examples:
+  zutto list set 5114 --status plan_to_watch
+  zutto list set 5114 --status completed --score 9 --episodes 64
+  zutto list set 21 --episodes 1000 --comments "still going"`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := parseAnimeID(args[0]); err != nil {
+			return err
+		}
+		update := listUpdateFromFlags(cmd)
+		if update == (mal.AnimeListUpdate{}) {
+			return fmt.Errorf("nothing to update: pass at least one of --status, --score, --episodes, ...")
+		}
+		return update.Validate()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		id, _ := parseAnimeID(args[0])
+		update := listUpdateFromFlags(cmd)
+
+		client := newClient()
+		status, err := client.List.UpdateAnimeContext(cmd.Context(), id, update)
+		if mal.IsNotFound(err) {
+			fmt.Fprintf(os.Stderr, "No anime with ID %d\n", id)
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error updating list: %v\n", describeError(err))
+			os.Exit(1)
+		}
+
+		out := newRenderer()
+		if !out.Text() {
+			renderValue(out, status)
+			return
+		}
+
+		fmt.Printf("Updated anime %d: %s, %d episodes watched", id, render.FormatStatus(status.Status), status.NumEpisodesWatched)
+		if status.Score > 0 {
+			fmt.Printf(", score %d", status.Score)
+		}
+		fmt.Println()
+	},
+}
+
+// listRemoveCmd represents the list remove command
+var listRemoveCmd = &cobra.Command{
+	Use:     "remove <anime-id>",
+	Aliases: []string{"rm"},
+	Short:   "Remove an anime from your list",
+	Long: `Remove an anime from your list.
+
+Examples:
+  zutto list remove 5114`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		_, err := parseAnimeID(args[0])
+		return err
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		id, _ := parseAnimeID(args[0])
+
+		client := newClient()
+		err := client.List.DeleteAnimeContext(cmd.Context(), id)
+		if errors.Is(err, mal.ErrNotOnList) {
+			fmt.Fprintf(os.Stderr, "Anime %d is not on your list\n", id)
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error removing anime from list: %v\n", describeError(err))
+			os.Exit(1)
+		}
+		out := newRenderer()
+		if !out.Text() {
+			renderValue(out, struct {
+				Removed int `json:"removed"`
+			}{id})
+			return
+		}
+		fmt.Printf("Removed anime %d from your list\n", id)
+	},
+}
+
+// listUpdateFromFlags builds an update from the flags explicitly set on cmd
+func listUpdateFromFlags(cmd *cobra.Command) mal.AnimeListUpdate {
+	var update mal.AnimeListUpdate
+	flags := cmd.Flags()
+
+	if flags.Changed("status") {
+		status, _ := flags.GetString("status")
+		update.Status = &status
+	}
+	if flags.Changed("score") {
+		score, _ := flags.GetInt("score")
+		update.Score = &score
+	}
+	if flags.Changed("episodes") {
+		episodes, _ := flags.GetInt("episodes")
+		update.NumWatchedEpisodes = &episodes
+	}
+	if flags.Changed("rewatching") {
+		rewatching, _ := flags.GetBool("rewatching")
+		update.IsRewatching = &rewatching
+	}
+	if flags.Changed("times-rewatched") {
+		times, _ := flags.GetInt("times-rewatched")
+		update.NumTimesRewatched = &times
+	}
+	if flags.Changed("priority") {
+		priority, _ := flags.GetInt("priority")
+		update.Priority = &priority
+	}
+	if flags.Changed("start-date") {
+		date, _ := flags.GetString("start-date")
+		update.StartDate = &date
+	}
+	if flags.Changed("finish-date") {
+		date, _ := flags.GetString("finish-date")
+		update.FinishDate = &date
+	}
+	if flags.Changed("tags") {
+		tags, _ := flags.GetStringSlice("tags")
+		update.Tags = &tags
+	}
+	if flags.Changed("comments") {
+		comments, _ := flags.GetString("comments")
+		update.Comments = &comments
+	}
+	return update
+}
+
+func parseAnimeID(arg string) (int, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("anime id must be a positive integer, got %q", arg)
+	}
+	return id, nil
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+
+	// Add subcommands
+	listCmd.AddCommand(listShowCmd)
+	listCmd.AddCommand(listSetCmd)
+	listCmd.AddCommand(listRemoveCmd)
+
+	// Show flags
+	listShowCmd.Flags().StringP("user", "u", "@me", "MyAnimeList user name")
+	listShowCmd.Flags().StringP("status", "s", "", "Only show entries with this status (watching, completed, on_hold, dropped, plan_to_watch)")
+	listShowCmd.Flags().String("sort", "", "Sort order (list_score, list_updated_at, anime_title, anime_start_date)")
+	listShowCmd.Flags().IntP("limit", "l", 100, "Maximum number of results to return (1-1000)")
+	listShowCmd.Flags().Int("offset", 0, "Offset for pagination")
+
+	// Set flags
+	listSetCmd.Flags().StringP("status", "s", "", "List status (watching, completed, on_hold, dropped, plan_to_watch)")
+	listSetCmd.Flags().Int("score", 0, "Score (0-10, 0 clears the score)")
+	listSetCmd.Flags().IntP("episodes", "e", 0, "Number of episodes watched")
+	listSetCmd.Flags().Bool("rewatching", false, "Mark as currently rewatching")
+	listSetCmd.Flags().Int("times-rewatched", 0, "Number of times rewatched")
+	listSetCmd.Flags().Int("priority", 0, "Priority (0-2)")
+	listSetCmd.Flags().String("start-date", "", "Date started (YYYY-MM-DD)")
+	listSetCmd.Flags().String("finish-date", "", "Date finished (YYYY-MM-DD)")
+	listSetCmd.Flags().StringSlice("tags", nil, "Comma-separated tags")
+	listSetCmd.Flags().String("comments", "", "Comments")
+}
@@ -5,8 +5,11 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
+	"github.com/bradleyyma/zutto/internal/mal"
+	"github.com/bradleyyma/zutto/internal/render"
 	"github.com/spf13/cobra"
 )
 
@@ -29,18 +32,219 @@ Examples:
 
 // mangaSearchCmd represents the manga search command
 var mangaSearchCmd = &cobra.Command{
-	Use:   "search [query]",
-	Short: "Search for manga",
+	Use:         "search [query]",
+	Short:       "Search for manga",
+	Annotations: map[string]string{limitAnnotation: "search"},
 	Long: `Search for manga on MyAnimeList.
-	
+
 Examples:
   zutto manga search "attack on titan"
   zutto manga search bleach --limit 20`,
 	Args: cobra.MinimumNArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+		if limit <= 0 || limit > 50 {
+			return fmt.Errorf("limit must be greater than 0 and less than or equal to 50, got %d", limit)
+		}
+		return nil
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		query := strings.Join(args, " ")
-		fmt.Printf("Searching for manga: %s\n", query)
-		// TODO: Implement manga search functionality
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		client := newClient()
+		results, err := mangaSource(client).SearchContext(cmd.Context(), query, limit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error searching for manga: %v\n", describeError(err))
+			os.Exit(1)
+		}
+
+		out := newRenderer()
+		if !out.Text() {
+			renderItems(out, results.Data)
+			return
+		}
+
+		if len(results.Data) == 0 {
+			fmt.Println("No manga found")
+			return
+		}
+
+		fmt.Printf("Found %d manga:\n\n", len(results.Data))
+		for i, manga := range results.Data {
+			title := manga.Node.PreferredTitle(preferredLanguage())
+			fmt.Printf("%d. %s (ID: %d)\n", i+1, title, manga.Node.ID)
+			if manga.Node.AlternativeTitles.En != "" && manga.Node.AlternativeTitles.En != title {
+				fmt.Printf("   English: %s\n", manga.Node.AlternativeTitles.En)
+			}
+		}
+	},
+}
+
+// mangaRankingCmd represents the manga ranking command
+var mangaRankingCmd = &cobra.Command{
+	Use:         "ranking",
+	Short:       "Get manga rankings",
+	Annotations: map[string]string{limitAnnotation: "ranking"},
+	Long: `Get manga rankings from MyAnimeList.
+
+Examples:
+  zutto manga ranking
+  zutto manga ranking --type manhwa
+  zutto manga ranking --type novels --limit 20`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		rankingType, _ := cmd.Flags().GetString("type")
+		if err := mal.ValidateMangaRankingType(rankingType); err != nil {
+			return err
+		}
+
+		limit, _ := cmd.Flags().GetInt("limit")
+		if limit <= 0 || limit > 100 {
+			return fmt.Errorf("limit must be between 1 and 100, got %d", limit)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		rankingType, _ := cmd.Flags().GetString("type")
+		limit, _ := cmd.Flags().GetInt("limit")
+		offset, _ := cmd.Flags().GetInt("offset")
+
+		client := newClient()
+		rankings, err := client.Manga.RankingsContext(cmd.Context(), rankingType, limit, offset)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error retrieving manga rankings: %v\n", describeError(err))
+			os.Exit(1)
+		}
+
+		out := newRenderer()
+		if !out.Text() {
+			renderItems(out, rankings.Data)
+			return
+		}
+
+		if len(rankings.Data) == 0 {
+			fmt.Println("No manga rankings found")
+			return
+		}
+
+		fmt.Printf("Top %d Manga Rankings (%s):\n\n", len(rankings.Data), rankingType)
+		for _, entry := range rankings.Data {
+			title := entry.Node.PreferredTitle(preferredLanguage())
+			fmt.Printf("%d. %s (ID: %d)\n", entry.Ranking.Rank, title, entry.Node.ID)
+			if entry.Node.AlternativeTitles.En != "" && entry.Node.AlternativeTitles.En != title {
+				fmt.Printf("    English: %s\n", entry.Node.AlternativeTitles.En)
+			}
+		}
+	},
+}
+
+// mangaDetailCmd represents the manga detail command
+var mangaDetailCmd = &cobra.Command{
+	Use:   "detail",
+	Short: "Get detailed information about a manga",
+	Long: `Get detailed information about a manga on MyAnimeList by ID or name.
+
+You must provide either --id or --name (but not both). A name is matched
+against titles, English and Japanese titles and synonyms; when several
+manga match about equally well you are asked to pick one, or shown the
+candidates if zutto is not running in a terminal.
+
+Examples:
+  zutto manga detail --id 2
+  zutto manga detail -i 2
+  zutto manga detail --name "Berserk"
+  zutto manga detail -n "one piece"`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		id, _ := cmd.Flags().GetInt("id")
+		name, _ := cmd.Flags().GetString("name")
+
+		if id == 0 && name == "" {
+			return fmt.Errorf("either --id or --name must be provided")
+		}
+		if id != 0 && name != "" {
+			return fmt.Errorf("cannot use both --id and --name flags together")
+		}
+		if id < 0 {
+			return fmt.Errorf("id must be a positive integer, got %d", id)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		id, _ := cmd.Flags().GetInt("id")
+		name, _ := cmd.Flags().GetString("name")
+
+		client := newClient()
+		out := newRenderer()
+
+		// If name is provided, resolve it to an ID first
+		if name != "" {
+			var err error
+			id, err = resolveMangaName(cmd.Context(), mangaSource(client), name)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error finding manga: %v\n", describeError(err))
+				os.Exit(1)
+			}
+		}
+
+		detail, err := mangaSource(client).DetailsContext(cmd.Context(), id)
+		if mal.IsNotFound(err) {
+			fmt.Fprintf(os.Stderr, "No manga with ID %d\n", id)
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting manga details: %v\n", describeError(err))
+			os.Exit(1)
+		}
+
+		if !out.Text() {
+			renderValue(out, detail)
+			return
+		}
+
+		title := detail.PreferredTitle(preferredLanguage())
+		fmt.Printf("Title: %s\n", title)
+		if detail.AlternativeTitles.En != "" && detail.AlternativeTitles.En != title {
+			fmt.Printf("English: %s\n", detail.AlternativeTitles.En)
+		}
+		if detail.MediaType != "" {
+			fmt.Printf("Type: %s\n", detail.MediaType)
+		}
+		if detail.Synopsis != "" {
+			fmt.Printf("Synopsis: %s\n", detail.Synopsis)
+		}
+		fmt.Printf("Volumes: %s\n", render.CountOrUnknown(detail.NumVolumes))
+		fmt.Printf("Chapters: %s\n", render.CountOrUnknown(detail.NumChapters))
+		fmt.Printf("Status: %s\n", detail.Status)
+		if detail.Mean > 0 {
+			fmt.Printf("Score: %.2f\n", detail.Mean)
+		}
+		if len(detail.Authors) > 0 {
+			authors := make([]string, 0, len(detail.Authors))
+			for _, author := range detail.Authors {
+				authors = append(authors, fmt.Sprintf("%s (%s)", author.Node.FullName(), author.Role))
+			}
+			fmt.Printf("Authors: %s\n", strings.Join(authors, ", "))
+		}
+		if len(detail.Serialization) > 0 {
+			magazines := make([]string, 0, len(detail.Serialization))
+			for _, s := range detail.Serialization {
+				magazines = append(magazines, s.Node.Name)
+			}
+			fmt.Printf("Serialization: %s\n", strings.Join(magazines, ", "))
+		}
+		if len(detail.Genres) > 0 {
+			genres := make([]string, 0, len(detail.Genres))
+			for _, g := range detail.Genres {
+				genres = append(genres, g.Name)
+			}
+			fmt.Printf("Genres: %s\n", strings.Join(genres, ", "))
+		}
+		if detail.StartDate != "" {
+			fmt.Printf("Start Date: %s\n", detail.StartDate)
+		}
+		if detail.EndDate != "" {
+			fmt.Printf("End Date: %s\n", detail.EndDate)
+		}
 	},
 }
 
@@ -49,7 +253,18 @@ func init() {
 
 	// Add subcommands
 	mangaCmd.AddCommand(mangaSearchCmd)
+	mangaCmd.AddCommand(mangaRankingCmd)
+	mangaCmd.AddCommand(mangaDetailCmd)
 
 	// Search flags
 	mangaSearchCmd.Flags().IntP("limit", "l", 10, "Maximum number of results to return (1-50)")
+
+	// Ranking flags
+	mangaRankingCmd.Flags().String("type", "all", "Type of ranking (all, manga, novels, oneshots, doujin, manhwa, manhua, bypopularity, favorite)")
+	mangaRankingCmd.Flags().IntP("limit", "l", 50, "Maximum number of results to return (1-100)")
+	mangaRankingCmd.Flags().Int("offset", 0, "Offset for pagination")
+
+	// Detail flags
+	mangaDetailCmd.Flags().IntP("id", "i", 0, "Manga ID")
+	mangaDetailCmd.Flags().StringP("name", "n", "", "Manga name (the closest title match is used)")
 }
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"os"
 
@@ -20,19 +19,36 @@ with the Zutto application via the MCP protocol.
 
 The server provides the following tools:
   - get_anime_ranking: Get anime rankings from MyAnimeList
+  - get_anime_details: Get detailed information about an anime
+  - batch_get_anime_details: Get details for several anime at once
+  - search_anime: Search for anime by title
+  - get_seasonal_anime: Get anime airing in a season
+  - resolve_anime: Find the ID for an anime name
+  - recommend_anime: Recommend anime from a user's highest-scored anime
+
+Each tool accepts a title_language parameter that adds a display_title in
+romaji, English or the native script. --title-language (or the
+title_language setting) picks the default.
+
+With --offline, searching, details and resolving read from the local
+mirror (see "zutto sync").
 
 Example:
   zutto mcp`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Create MCP server
-		server, err := mcp.NewMCPServer()
+		server, err := mcp.NewMCPServer(newClient())
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error creating MCP server: %v\n", err)
 			os.Exit(1)
 		}
+		server.SetTitleLanguage(preferredLanguage())
+		if offline {
+			server.SetAnimeSource(openMirror(true).Anime)
+		}
 
 		// Run the server
-		if err := server.Run(context.Background()); err != nil {
+		if err := server.Run(cmd.Context()); err != nil {
 			fmt.Fprintf(os.Stderr, "Error running MCP server: %v\n", err)
 			os.Exit(1)
 		}
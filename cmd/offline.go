@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+	"github.com/bradleyyma/zutto/internal/mirror"
+)
+
+// errOffline is returned for requests that would go to MAL under --offline
+var errOffline = errors.New("not available offline")
+
+// offlineTransport fails every request, so commands without a mirror
+// fallback can only answer from the response cache under --offline
+type offlineTransport struct{}
+
+func (offlineTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errOffline
+}
+
+// localMirror is opened on first use by openMirror
+var localMirror *mirror.Mirror
+
+// openMirror opens the local mirror, exiting if it cannot. With mustExist,
+// a missing mirror is an error telling the user to sync first.
+func openMirror(mustExist bool) *mirror.Mirror {
+	if localMirror != nil {
+		return localMirror
+	}
+	path, err := mirror.DefaultPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if _, err := os.Stat(path); mustExist && os.IsNotExist(err) {
+		fmt.Fprintln(os.Stderr, "Error: there is no local mirror yet; run `zutto sync` first")
+		os.Exit(1)
+	}
+	m, err := mirror.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	localMirror = m
+	return m
+}
+
+// recordAnime has the anime client fetches added to the local mirror and
+// its search index, so `anime search --local` covers everything zutto has
+// fetched. If the mirror cannot be opened nothing is recorded.
+func recordAnime(client *mal.Client) {
+	if offline {
+		return
+	}
+	if localMirror == nil {
+		path, err := mirror.DefaultPath()
+		if err != nil {
+			return
+		}
+		m, err := mirror.Open(path)
+		if err != nil {
+			return
+		}
+		localMirror = m
+	}
+	client.SetAnimeRecorder(localMirror.Anime)
+}
+
+// animeSource returns where anime lookups are read from: the local mirror
+// under --offline, MAL otherwise
+func animeSource(client *mal.Client) mal.AnimeSource {
+	if offline {
+		return openMirror(true).Anime
+	}
+	return client.Anime
+}
+
+// mangaSource is like animeSource for manga
+func mangaSource(client *mal.Client) mal.MangaSource {
+	if offline {
+		return openMirror(true).Manga
+	}
+	return client.Manga
+}
+
+// listSource is like animeSource for users' lists
+func listSource(client *mal.Client) mal.ListSource {
+	if offline {
+		return openMirror(true).List
+	}
+	return client.List
+}
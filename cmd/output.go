@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bradleyyma/zutto/internal/render"
+)
+
+var (
+	// outputFormat, outputColumns and outputTemplate select how command
+	// results are written; see internal/render
+	outputFormat   string
+	outputColumns  []string
+	outputTemplate string
+)
+
+func outputOptions() render.Options {
+	return render.Options{
+		Format:   render.Format(outputFormat),
+		Columns:  outputColumns,
+		Template: outputTemplate,
+	}
+}
+
+// validateOutputFlags checks --output, --columns and --template before any
+// request is made
+func validateOutputFlags() error {
+	_, err := render.New(io.Discard, outputOptions())
+	return err
+}
+
+// newRenderer returns a renderer for stdout using the output flags.
+// Commands print their usual text when out.Text() is true and pass their
+// results to out otherwise.
+func newRenderer() *render.Renderer {
+	out, err := render.New(os.Stdout, outputOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return out
+}
+
+// renderItems writes a list result and flushes out
+func renderItems[T any](out *render.Renderer, items []T) {
+	for _, item := range items {
+		renderItem(out, item)
+	}
+	flushOutput(out)
+}
+
+// renderItem writes one record of a list result
+func renderItem(out *render.Renderer, item any) {
+	if err := out.Item(item); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// renderValue writes a single result and flushes out
+func renderValue(out *render.Renderer, v any) {
+	if err := out.Value(v); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		os.Exit(1)
+	}
+	flushOutput(out)
+}
+
+func flushOutput(out *render.Renderer) {
+	if err := out.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		os.Exit(1)
+	}
+}
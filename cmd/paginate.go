@@ -0,0 +1,37 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// addPaginationFlags adds --all and --max to a command backed by a paginated
+// MAL endpoint
+func addPaginationFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("all", false, "Follow pagination and return every result")
+	cmd.Flags().Int("max", 0, "Follow pagination until this many results have been returned")
+}
+
+func validatePaginationFlags(cmd *cobra.Command) error {
+	all, _ := cmd.Flags().GetBool("all")
+	maxResults, _ := cmd.Flags().GetInt("max")
+	if maxResults < 0 {
+		return fmt.Errorf("max must be a positive integer, got %d", maxResults)
+	}
+	if all && maxResults > 0 {
+		return fmt.Errorf("cannot use both --all and --max flags together")
+	}
+	return nil
+}
+
+// paginationMax reports whether the command should paginate and the item cap
+// to apply (0 for no cap)
+func paginationMax(cmd *cobra.Command) (int, bool) {
+	all, _ := cmd.Flags().GetBool("all")
+	maxResults, _ := cmd.Flags().GetInt("max")
+	return maxResults, all || maxResults > 0
+}
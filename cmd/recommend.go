@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+	"github.com/bradleyyma/zutto/internal/render"
+	"github.com/spf13/cobra"
+)
+
+// maxReasons is how many reasons are printed for each recommendation
+const maxReasons = 3
+
+// recommendCmd represents the recommend command
+var recommendCmd = &cobra.Command{
+	Use:   "recommend",
+	Short: "Recommend anime based on your highest-scored completed anime",
+	Long: `Recommend anime you have not added to your list yet.
+
+zutto takes your highest-scored completed anime and adds up the
+recommendations MyAnimeList users made for each of them. Anime recommended
+from more of your favorites, from higher-scored ones, or by more users come
+first, and anime MyAnimeList also suggests for you get a boost. Each
+recommendation lists the anime it came from.
+
+Your own recommendations require "zutto auth login"; --user draws from
+another user's public list instead, without MyAnimeList's suggestions.
+
+Examples:
+  zutto recommend
+  zutto recommend --seeds 20 --limit 25
+  zutto recommend --user someone
+  zutto recommend --output json`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		seeds, _ := cmd.Flags().GetInt("seeds")
+		if seeds < 1 || seeds > 100 {
+			return fmt.Errorf("seeds must be between 1 and 100, got %d", seeds)
+		}
+		limit, _ := cmd.Flags().GetInt("limit")
+		if limit < 1 || limit > 100 {
+			return fmt.Errorf("limit must be between 1 and 100, got %d", limit)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		user, _ := cmd.Flags().GetString("user")
+		seeds, _ := cmd.Flags().GetInt("seeds")
+		limit, _ := cmd.Flags().GetInt("limit")
+		noSuggestions, _ := cmd.Flags().GetBool("no-suggestions")
+
+		client := newClient()
+		result, err := client.List.RecommendContext(cmd.Context(), mal.RecommendOptions{
+			User:          user,
+			Seeds:         seeds,
+			Limit:         limit,
+			NoSuggestions: noSuggestions,
+		})
+		if mal.IsNotFound(err) {
+			fmt.Fprintf(os.Stderr, "No user named %s\n", user)
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting recommendations: %v\n", describeError(err))
+			os.Exit(1)
+		}
+		for _, e := range result.Errors {
+			fmt.Fprintf(os.Stderr, "Warning: skipped recommendations from anime %d: %v\n", e.ID, describeError(e.Err))
+		}
+		if result.SuggestionsErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: left out MyAnimeList's suggestions: %v\n", describeError(result.SuggestionsErr))
+		}
+
+		out := newRenderer()
+		if !out.Text() {
+			renderItems(out, result.Recommendations)
+			return
+		}
+
+		if len(result.Seeds) == 0 {
+			fmt.Println("No scored completed anime on the list to recommend from")
+			return
+		}
+		if len(result.Recommendations) == 0 {
+			fmt.Println("No recommendations found")
+			return
+		}
+
+		lang := preferredLanguage()
+		fmt.Printf("Recommended from %d of your highest-scored anime:\n\n", len(result.Seeds))
+		for i, rec := range result.Recommendations {
+			anime := rec.Anime
+			fmt.Printf("%2d. %s (ID: %d)\n", i+1, anime.PreferredTitle(lang), anime.ID)
+
+			var details []string
+			if anime.MediaType != "" {
+				details = append(details, anime.MediaType)
+			}
+			details = append(details, render.CountOrUnknown(anime.NumEpisodes)+" episodes")
+			if anime.Mean > 0 {
+				details = append(details, fmt.Sprintf("score %.2f", anime.Mean))
+			}
+			fmt.Printf("    %s\n", strings.Join(details, ", "))
+
+			if len(rec.Reasons) > 0 {
+				var reasons []string
+				for _, r := range rec.Reasons[:min(len(rec.Reasons), maxReasons)] {
+					reasons = append(reasons, fmt.Sprintf("%s (you scored %d, %d %s)", r.Anime.PreferredTitle(lang),
+						r.UserScore, r.NumRecommendations, plural(r.NumRecommendations, "recommendation")))
+				}
+				if more := len(rec.Reasons) - maxReasons; more > 0 {
+					reasons = append(reasons, fmt.Sprintf("%d more", more))
+				}
+				fmt.Printf("    Because you liked %s\n", strings.Join(reasons, ", "))
+			}
+			if rec.SuggestedByMAL {
+				fmt.Println("    Also suggested for you by MyAnimeList")
+			}
+		}
+	},
+}
+
+// plural returns word, or word with an s when n is not 1
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
+
+func init() {
+	rootCmd.AddCommand(recommendCmd)
+
+	recommendCmd.Flags().StringP("user", "u", "@me", "MyAnimeList user name")
+	recommendCmd.Flags().Int("seeds", mal.DefaultRecommendSeeds, "Number of highest-scored completed anime to draw from (1-100)")
+	recommendCmd.Flags().IntP("limit", "l", mal.DefaultRecommendLimit, "Maximum number of recommendations (1-100)")
+	recommendCmd.Flags().Bool("no-suggestions", false, "Leave out MyAnimeList's personalized suggestions")
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+	"golang.org/x/term"
+)
+
+// maxSuggestions is how many candidates are offered for an ambiguous name
+const maxSuggestions = 5
+
+// resolveAnimeName turns a name into an anime ID by searching src. A clear
+// match is used directly; otherwise the user picks from the candidates when
+// running in a terminal, or gets an error listing them.
+func resolveAnimeName(ctx context.Context, src mal.AnimeSource, name string) (int, error) {
+	resolution, err := mal.ResolveAnime(ctx, src, name, 10)
+	if err != nil {
+		return 0, err
+	}
+	if len(resolution.Candidates) == 0 {
+		return 0, fmt.Errorf("no anime found with name: %s", name)
+	}
+	if best, ok := resolution.Best(); ok {
+		return best.Anime.ID, nil
+	}
+
+	candidates := resolution.Candidates[:min(len(resolution.Candidates), maxSuggestions)]
+	labels := make([]string, len(candidates))
+	for i := range candidates {
+		labels[i] = formatCandidate(&candidates[i])
+	}
+	i, err := chooseCandidate("anime", name, labels)
+	if err != nil {
+		return 0, err
+	}
+	return candidates[i].Anime.ID, nil
+}
+
+// resolveMangaName is like resolveAnimeName for manga
+func resolveMangaName(ctx context.Context, src mal.MangaSource, name string) (int, error) {
+	resolution, err := mal.ResolveManga(ctx, src, name, 10)
+	if err != nil {
+		return 0, err
+	}
+	if len(resolution.Candidates) == 0 {
+		return 0, fmt.Errorf("no manga found with name: %s", name)
+	}
+	if best, ok := resolution.Best(); ok {
+		return best.Manga.ID, nil
+	}
+
+	candidates := resolution.Candidates[:min(len(resolution.Candidates), maxSuggestions)]
+	labels := make([]string, len(candidates))
+	for i := range candidates {
+		labels[i] = formatMangaCandidate(&candidates[i])
+	}
+	i, err := chooseCandidate("manga", name, labels)
+	if err != nil {
+		return 0, err
+	}
+	return candidates[i].Manga.ID, nil
+}
+
+// chooseCandidate returns the index of the candidate the user picks for an
+// ambiguous name. Outside a terminal it returns an error listing them.
+func chooseCandidate(kind, name string, labels []string) (int, error) {
+	if !interactive() {
+		var b strings.Builder
+		fmt.Fprintf(&b, "%q matches several %s, pass an ID instead:", name, kind)
+		for _, label := range labels {
+			fmt.Fprintf(&b, "\n  %s", label)
+		}
+		return 0, fmt.Errorf("%s", b.String())
+	}
+	return promptCandidate(kind, name, labels)
+}
+
+// promptCandidate asks the user on stderr to pick one of the labelled
+// candidates, returning its index
+func promptCandidate(kind, name string, labels []string) (int, error) {
+	fmt.Fprintf(os.Stderr, "%q matches several %s:\n", name, kind)
+	for i, label := range labels {
+		fmt.Fprintf(os.Stderr, "  %d) %s\n", i+1, label)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Fprintf(os.Stderr, "Choose 1-%d (enter to cancel): ", len(labels))
+		line, err := reader.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return 0, fmt.Errorf("no %s chosen", kind)
+		}
+		if n, convErr := strconv.Atoi(line); convErr == nil && n >= 1 && n <= len(labels) {
+			return n - 1, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("no %s chosen", kind)
+		}
+	}
+}
+
+// formatCandidate describes a candidate on one line, e.g.
+// "Naruto (ID: 20, tv, fall 2002, 92% match)"
+func formatCandidate(c *mal.AnimeCandidate) string {
+	details := []string{fmt.Sprintf("ID: %d", c.Anime.ID)}
+	if c.Anime.MediaType != "" {
+		details = append(details, c.Anime.MediaType)
+	}
+	if season := c.Anime.StartSeason; season != nil {
+		details = append(details, fmt.Sprintf("%s %d", season.Season, season.Year))
+	}
+	details = append(details, fmt.Sprintf("%.0f%% match", c.Confidence*100))
+	return fmt.Sprintf("%s (%s)", c.Anime.PreferredTitle(preferredLanguage()), strings.Join(details, ", "))
+}
+
+// formatMangaCandidate describes a manga candidate on one line, e.g.
+// "Berserk (ID: 2, 100% match)"
+func formatMangaCandidate(c *mal.MangaCandidate) string {
+	return fmt.Sprintf("%s (ID: %d, %.0f%% match)", c.Manga.PreferredTitle(preferredLanguage()), c.Manga.ID, c.Confidence*100)
+}
+
+// interactive reports whether the user can answer prompts
+func interactive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stderr.Fd()))
+}
@@ -4,8 +4,15 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"context"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"time"
 
+	"github.com/bradleyyma/zutto/internal/config"
+	"github.com/bradleyyma/zutto/internal/mal"
 	"github.com/spf13/cobra"
 )
 
@@ -13,34 +20,196 @@ import (
 var rootCmd = &cobra.Command{
 	Use:   "zutto",
 	Short: "A CLI tool to manage your anime lists",
-	Long: `A longer description that spans multiple lines and likely contains
-examples and usage of using your application. For example:
+	Long: `zutto searches MyAnimeList and manages your anime list from the
+terminal. It can also run as an MCP server for AI assistants.
 
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
-	// Uncomment the following line if your bare application
-	// has an action associated with it:
-	// Run: func(cmd *cobra.Command, args []string) { },
+Settings such as the client ID and default output format are read from
+the config file (see "zutto config path"). Environment variables override
+the file, and flags override both. Use --profile to select a named
+profile from the file.
+
+Examples:
+  zutto anime search "one piece"
+  zutto list show --status watching
+  zutto --profile work anime ranking --output json`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := loadSettings(cmd); err != nil {
+			return err
+		}
+		if titleLanguage != "" {
+			if err := mal.ValidateTitleLanguage(titleLanguage); err != nil {
+				return err
+			}
+		}
+		return validateOutputFlags()
+	},
 }
 
+var (
+	// requestTimeout bounds each MAL API request made by a command
+	requestTimeout time.Duration
+
+	// noCache disables the response cache; refreshCache bypasses cache
+	// reads but stores the fresh responses
+	noCache      bool
+	refreshCache bool
+
+	// offline reads search, detail and list commands from the local
+	// mirror and keeps every other request off the network
+	offline bool
+
+	// configFile and profileName select the config file and profile;
+	// settings holds the values resolved from them
+	configFile  string
+	profileName string
+	settings    = &config.Settings{}
+
+	// titleLanguage is the language titles are printed in (romaji,
+	// english, native)
+	titleLanguage string
+)
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// Interrupting the process cancels the command's context, aborting any
+// in-flight requests.
 func Execute() {
-	err := rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := rootCmd.ExecuteContext(ctx)
 	if err != nil {
 		os.Exit(1)
 	}
 }
 
-func init() {
-	// Here you will define your flags and configuration settings.
-	// Cobra supports persistent flags, which, if defined here,
-	// will be global for your application.
+// newClient creates a MAL client that authenticates with the stored token
+// when the user has logged in
+func newClient() *mal.Client {
+	client := mal.NewClient(nil, settings.ClientID)
+	if offline {
+		// Only cached responses can be served, so fail fast
+		client = mal.NewClient(&http.Client{Transport: offlineTransport{}}, settings.ClientID)
+		client.SetRetryPolicy(mal.RetryPolicy{})
+	}
+	client.SetRequestTimeout(requestTimeout)
+	// MAL does not publish its limits; a few requests per second stays
+	// clear of 429s even for large batches
+	client.SetRateLimit(3, 3)
+	if !noCache {
+		if cache, err := mal.DefaultDiskCache(); err == nil {
+			policy := mal.DefaultCachePolicy
+			if ttl := settings.Cache.DetailsTTL; ttl > 0 {
+				policy.DetailsTTL = ttl
+			}
+			if ttl := settings.Cache.SearchTTL; ttl > 0 {
+				policy.SearchTTL = ttl
+			}
+			if ttl := settings.Cache.RankingsTTL; ttl > 0 {
+				policy.RankingsTTL = ttl
+			}
+			if ttl := settings.Cache.SeasonalTTL; ttl > 0 {
+				policy.SeasonalTTL = ttl
+			}
+			policy.Refresh = refreshCache
+			client.SetCache(cache, policy)
+		}
+	}
+	if settings.NSFW != nil {
+		client.SetNSFW(*settings.NSFW)
+	}
+	// Refreshing an expired token is a network request, and nothing sent
+	// offline reaches MAL anyway
+	if auth, err := newAuthenticator(); err == nil && !offline {
+		client.SetTokenSource(auth.TokenSource(context.Background()))
+	}
+	recordAnime(client)
+	return client
+}
+
+// preferredLanguage returns the title language selected by --title-language
+// or the config file
+func preferredLanguage() mal.TitleLanguage {
+	return mal.TitleLanguage(titleLanguage)
+}
+
+// configPath returns the config file selected by --config or ZUTTO_CONFIG,
+// or the default location
+func configPath() (string, error) {
+	if configFile != "" {
+		return configFile, nil
+	}
+	if path := os.Getenv("ZUTTO_CONFIG"); path != "" {
+		return path, nil
+	}
+	return config.DefaultPath()
+}
 
-	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.zutto.yaml)")
+// activeProfile returns the profile selected by --profile or ZUTTO_PROFILE
+func activeProfile() string {
+	if profileName != "" {
+		return profileName
+	}
+	return os.Getenv("ZUTTO_PROFILE")
+}
+
+// loadSettings resolves settings from the config file and environment and
+// uses them as defaults for flags not passed on the command line
+func loadSettings(cmd *cobra.Command) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	file, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+	settings, err = file.Resolve(activeProfile())
+	if err != nil {
+		return err
+	}
 
-	// Cobra also supports local flags, which will only run
-	// when this action is called directly.
-	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	flags := cmd.Flags()
+	if settings.Output != "" && !flags.Changed("output") {
+		outputFormat = settings.Output
+	}
+	if settings.TitleLanguage != "" && !flags.Changed("title-language") {
+		titleLanguage = settings.TitleLanguage
+	}
+
+	// Commands name the config limit that applies to their --limit flag
+	var limit int
+	switch cmd.Annotations[limitAnnotation] {
+	case "search":
+		limit = settings.Limits.Search
+	case "ranking":
+		limit = settings.Limits.Ranking
+	case "season":
+		limit = settings.Limits.Season
+	case "list":
+		limit = settings.Limits.List
+	}
+	if limit > 0 && flags.Lookup("limit") != nil && !flags.Changed("limit") {
+		if err := flags.Set("limit", strconv.Itoa(limit)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// limitAnnotation names the config limit (search, ranking, season, list)
+// used as a command's default --limit
+const limitAnnotation = "zutto/limit"
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "Config file (default is $XDG_CONFIG_HOME/zutto/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Named profile from the config file")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Do not read or write the response cache")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "Read search, detail and list commands from the local mirror (see zutto sync) and make no network requests")
+	rootCmd.PersistentFlags().BoolVar(&refreshCache, "refresh", false, "Ignore cached responses and fetch fresh data")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", "text", "Output format (text, json, jsonl, yaml, csv, tsv)")
+	rootCmd.PersistentFlags().StringSliceVar(&outputColumns, "columns", nil, "Columns to show: table columns for text output (e.g. rank,title,score), JSON paths for csv/tsv (e.g. node.id,node.title)")
+	rootCmd.PersistentFlags().StringVar(&outputTemplate, "template", "", "Go template applied to each result, e.g. '{{.node.id}} {{.node.title}}'")
+	rootCmd.PersistentFlags().StringVar(&titleLanguage, "title-language", "", "Language to show titles in (romaji, english, native); falls back to romaji")
+	rootCmd.PersistentFlags().DurationVar(&requestTimeout, "request-timeout", 30*time.Second, "Deadline for each MyAnimeList API request (0 disables)")
 }
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+	"github.com/bradleyyma/zutto/internal/render"
+	"github.com/spf13/cobra"
+)
+
+// maxChartWidth keeps charts readable on wide terminals
+const maxChartWidth = 80
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show statistics for an anime list",
+	Long: `Summarize your anime list, or another user's public list: total watch
+time, completion rate, how you score compared to the MyAnimeList
+community, your score distribution, top genres and studios, and how many
+anime you started and completed each year.
+
+Watch time is episodes watched times each anime's average episode length.
+The completion rate counts everything but plan to watch as started. Yearly
+activity uses the start and finish dates on the list, so entries without
+dates are left out.
+
+Use --output json (or yaml) to export the numbers instead of charts.
+
+Examples:
+  zutto stats
+  zutto stats --user someone
+  zutto stats --output json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		user, _ := cmd.Flags().GetString("user")
+
+		client := newClient()
+		stats, err := client.List.StatsContext(cmd.Context(), user)
+		if mal.IsNotFound(err) {
+			fmt.Fprintf(os.Stderr, "No user named %s\n", user)
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error retrieving anime list: %v\n", describeError(err))
+			os.Exit(1)
+		}
+
+		out := newRenderer()
+		if !out.Text() {
+			renderValue(out, stats)
+			return
+		}
+
+		if stats.Entries == 0 {
+			fmt.Println("No anime found on list")
+			return
+		}
+		printStats(stats)
+	},
+}
+
+// printStats writes stats as a summary followed by charts
+func printStats(stats *mal.ListStats) {
+	var statuses []string
+	for _, s := range stats.Statuses {
+		statuses = append(statuses, fmt.Sprintf("%d %s", s.Count, render.FormatStatus(s.Name)))
+	}
+	fmt.Printf("Entries:          %d (%s)\n", stats.Entries, strings.Join(statuses, ", "))
+	fmt.Printf("Episodes watched: %d\n", stats.EpisodesWatched)
+	fmt.Printf("Watch time:       %s\n", formatWatchTime(stats.WatchMinutes))
+	fmt.Printf("Completion rate:  %.0f%% of started anime\n", stats.CompletionRate*100)
+	if stats.MeanScore > 0 {
+		fmt.Printf("Mean score:       %.2f\n", stats.MeanScore)
+		fmt.Printf("Compared to MAL:  %s on average, %.2f apart per anime\n",
+			formatDeviation(stats.MeanDeviation), stats.MeanAbsoluteDeviation)
+	}
+
+	if stats.MeanScore > 0 {
+		chart := newChart()
+		for i := len(stats.ScoreDistribution) - 1; i >= 0; i-- {
+			s := stats.ScoreDistribution[i]
+			chart.Add(strconv.Itoa(s.Score), float64(s.Count), strconv.Itoa(s.Count))
+		}
+		printChart("Score distribution", chart)
+	}
+
+	printCounts("Media types", stats.MediaTypes)
+	printCounts("Top genres", stats.TopGenres)
+	printCounts("Top studios", stats.TopStudios)
+
+	if len(stats.Years) > 0 {
+		chart := newChart()
+		for _, y := range stats.Years {
+			chart.Add(strconv.Itoa(y.Year), float64(y.Completed),
+				fmt.Sprintf("%d completed, %d started", y.Completed, y.Started))
+		}
+		printChart("Completed by year", chart)
+	}
+}
+
+// printCounts charts how many entries have each name, with the user's
+// mean score for them
+func printCounts(title string, counts []mal.StatCount) {
+	if len(counts) == 0 {
+		return
+	}
+	chart := newChart()
+	for _, c := range counts {
+		note := strconv.Itoa(c.Count)
+		if c.MeanScore > 0 {
+			note += fmt.Sprintf(" (mean %.2f)", c.MeanScore)
+		}
+		chart.Add(c.Name, float64(c.Count), note)
+	}
+	printChart(title, chart)
+}
+
+func newChart() *render.BarChart {
+	width := render.TerminalWidth(os.Stdout)
+	if width == 0 || width > maxChartWidth {
+		width = maxChartWidth
+	}
+	return render.NewBarChart(width, render.ColorEnabled(os.Stdout))
+}
+
+func printChart(title string, chart *render.BarChart) {
+	fmt.Printf("\n%s:\n", title)
+	if err := chart.Write(os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// formatWatchTime shows minutes as days and hours, e.g. "12 days 4 hours"
+func formatWatchTime(minutes int) string {
+	days, hours := minutes/(24*60), minutes%(24*60)/60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%d %s %d %s", days, plural(days, "day"), hours, plural(hours, "hour"))
+	case hours > 0:
+		return fmt.Sprintf("%d %s %d %s", hours, plural(hours, "hour"), minutes%60, plural(minutes%60, "minute"))
+	}
+	return fmt.Sprintf("%d %s", minutes, plural(minutes, "minute"))
+}
+
+// formatDeviation describes how a user's scores compare to the community's
+func formatDeviation(deviation float64) string {
+	switch {
+	case deviation > 0:
+		return fmt.Sprintf("%.2f higher", deviation)
+	case deviation < 0:
+		return fmt.Sprintf("%.2f lower", -deviation)
+	}
+	return "the same"
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().StringP("user", "u", "@me", "MyAnimeList user name")
+}
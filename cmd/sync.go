@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bradleyyma/zutto/internal/mirror"
+	"github.com/spf13/cobra"
+)
+
+// syncCmd represents the sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Mirror your lists and their titles for offline use",
+	Long: `Copy your anime and manga lists, and the details of every title on them,
+into a local SQLite database. With --offline, search, detail and list
+commands read from this mirror instead of MyAnimeList.
+
+Syncing is incremental. Your lists are fetched whole, but only entries whose
+updated_at changed are rewritten, and details are only fetched for titles
+that are new to the mirror or that MyAnimeList has updated since. Use
+--full to fetch every title's details again.
+
+Examples:
+  zutto sync
+  zutto sync --type anime
+  zutto sync --user someone
+  zutto --offline anime search "one piece"`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if offline {
+			return fmt.Errorf("sync needs MyAnimeList and cannot run with --offline")
+		}
+		listType, _ := cmd.Flags().GetString("type")
+		switch listType {
+		case "all", "anime", "manga":
+			return nil
+		}
+		return fmt.Errorf("invalid list type %q, must be one of: all, anime, manga", listType)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		user, _ := cmd.Flags().GetString("user")
+		listType, _ := cmd.Flags().GetString("type")
+		full, _ := cmd.Flags().GetBool("full")
+
+		// Sync compares each title's updated_at with the mirror's, so cached
+		// details would hide changes; fresh responses still refill the cache
+		refreshCache = true
+		client := newClient()
+		// Sync stores the details it fetches itself
+		client.SetAnimeRecorder(nil)
+		m := openMirror(false)
+		defer m.Close()
+		result, err := m.Sync(cmd.Context(), client, mirror.SyncOptions{
+			User:  user,
+			Anime: listType != "manga",
+			Manga: listType != "anime",
+			Full:  full,
+			Progress: func(kind string, done, total int) {
+				fmt.Fprintf(os.Stderr, "Fetched %s details: %d/%d\n", kind, done, total)
+			},
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error syncing: %v\n", describeError(err))
+			os.Exit(1)
+		}
+
+		out := newRenderer()
+		if !out.Text() {
+			renderValue(out, result)
+			return
+		}
+
+		fmt.Printf("Synced %s's lists\n", result.User)
+		printSyncStats("anime", result.Anime)
+		printSyncStats("manga", result.Manga)
+	},
+}
+
+func printSyncStats(kind string, stats *mirror.SyncStats) {
+	if stats == nil {
+		return
+	}
+	fmt.Printf("  %s: %d entries (%d added, %d updated, %d removed), %d details fetched\n",
+		kind, stats.Entries, stats.Added, stats.Updated, stats.Removed, stats.DetailsFetched)
+	for _, err := range stats.Errors {
+		fmt.Fprintf(os.Stderr, "Warning: could not fetch %s %v\n", kind, err)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+
+	syncCmd.Flags().StringP("user", "u", "@me", "MyAnimeList user name")
+	syncCmd.Flags().StringP("type", "t", "all", "Lists to mirror (all, anime, manga)")
+	syncCmd.Flags().Bool("full", false, "Fetch the details of every listed title again")
+}
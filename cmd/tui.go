@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bradleyyma/zutto/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// tuiCmd represents the tui command
+var tuiCmd = &cobra.Command{
+	Use:   "tui [query]",
+	Short: "Browse anime in a full-screen terminal UI",
+	Long: `Open a full-screen browser for searching anime, ranking lists and the
+anime you are watching.
+
+The left pane lists results and the right pane shows the selected anime's
+synopsis, score, episodes and air dates. Tabs across the top switch between
+search, the ranking types and your watching list.
+
+Keys:
+  /             focus the search box (enter searches, esc leaves it)
+  tab, ←/→      switch tabs
+  ↑/↓, j/k      move through results
+  a             add the selected anime to your plan to watch list
+  +             record the next episode as watched
+  r             reload the current tab
+  q             quit
+
+Adding to your list and recording episodes require 'zutto auth login'.
+
+Examples:
+  zutto tui
+  zutto tui "frieren"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := tui.Options{
+			TitleLanguage: preferredLanguage(),
+			Query:         strings.Join(args, " "),
+		}
+		if err := tui.Run(cmd.Context(), newClient(), opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", describeError(err))
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+}
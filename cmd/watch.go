@@ -0,0 +1,100 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+	"github.com/bradleyyma/zutto/internal/render"
+	"github.com/spf13/cobra"
+)
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch <anime-id|name>",
+	Short: "Record that you watched the next episode",
+	Long: `Record progress on an anime in your list.
+
+By default the watched episode count goes up by one. The status moves from
+plan to watch to watching, and to completed once the last episode is
+reached; start and finish dates are filled in automatically.
+
+Use --rewatch to record progress on a rewatch of a completed anime. When the
+rewatch reaches the last episode the rewatch count goes up by one.
+
+Examples:
+  zutto watch 5114
+  zutto watch "frieren"
+  zutto watch 5114 --to 12
+  zutto watch 5114 --rewatch`,
+	Args: cobra.MinimumNArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		to, _ := cmd.Flags().GetInt("to")
+		if to < 0 {
+			return fmt.Errorf("--to must be a positive integer, got %d", to)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		to, _ := cmd.Flags().GetInt("to")
+		rewatch, _ := cmd.Flags().GetBool("rewatch")
+
+		client := newClient()
+
+		query := strings.Join(args, " ")
+		id, err := strconv.Atoi(query)
+		if err != nil || id <= 0 {
+			id, err = resolveAnimeName(cmd.Context(), client.Anime, query)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error finding anime: %v\n", describeError(err))
+				os.Exit(1)
+			}
+		}
+
+		result, err := client.List.WatchContext(cmd.Context(), id, mal.WatchOptions{To: to, Rewatch: rewatch})
+		if errors.Is(err, mal.ErrAlreadyCompleted) {
+			fmt.Fprintf(os.Stderr, "Anime %d is already completed; use --rewatch to record a rewatch\n", id)
+			os.Exit(1)
+		}
+		if mal.IsNotFound(err) {
+			fmt.Fprintf(os.Stderr, "No anime with ID %d\n", id)
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error updating progress: %v\n", describeError(err))
+			os.Exit(1)
+		}
+
+		out := newRenderer()
+		if !out.Text() {
+			renderValue(out, result)
+			return
+		}
+
+		current := result.Current
+		fmt.Printf("%s (ID: %d)\n", result.PreferredTitle(preferredLanguage()), id)
+		fmt.Printf("Progress: %s episodes\n", render.FormatProgress(current.NumEpisodesWatched, result.NumEpisodes))
+		if result.Previous == nil || result.Previous.Status != current.Status {
+			fmt.Printf("Status: %s\n", render.FormatStatus(current.Status))
+		}
+		if current.IsRewatching {
+			fmt.Println("Rewatching")
+		}
+		if result.Previous != nil && current.NumTimesRewatched > result.Previous.NumTimesRewatched {
+			fmt.Printf("Rewatch complete (rewatched %d times)\n", current.NumTimesRewatched)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().Int("to", 0, "Set the watched episode count instead of adding one")
+	watchCmd.Flags().Bool("rewatch", false, "Record progress on a rewatch of a completed anime")
+}
@@ -0,0 +1,234 @@
+// Package backup converts anime and manga lists to and from files: MAL's
+// XML export format, CSV and JSON.
+//
+// All formats go through List, a format-neutral copy of a list where
+// anime and manga share fields: Progress is episodes watched or chapters
+// read, Repeating is rewatching or rereading, and so on.
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+)
+
+// ListType says whether a list holds anime or manga
+type ListType string
+
+const (
+	Anime ListType = "anime"
+	Manga ListType = "manga"
+)
+
+// ListTypes are the accepted list types, for help text and validation
+var ListTypes = []ListType{Anime, Manga}
+
+// ValidateListType checks that t is one of ListTypes
+func ValidateListType(t string) error {
+	for _, lt := range ListTypes {
+		if string(lt) == t {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid list type %q, must be one of: anime, manga", t)
+}
+
+// Format is a list file format
+type Format string
+
+const (
+	MALXML Format = "mal-xml"
+	CSV    Format = "csv"
+	JSON   Format = "json"
+)
+
+// Formats are the supported file formats, for help text and validation
+var Formats = []Format{MALXML, CSV, JSON}
+
+// ValidateFormat checks that f is one of Formats
+func ValidateFormat(f string) error {
+	names := make([]string, len(Formats))
+	for i, format := range Formats {
+		if string(format) == f {
+			return nil
+		}
+		names[i] = string(format)
+	}
+	return fmt.Errorf("invalid format %q, must be one of: %s", f, strings.Join(names, ", "))
+}
+
+// List is an anime or manga list
+type List struct {
+	Type     ListType `json:"type"`
+	UserID   int      `json:"user_id,omitempty"`
+	UserName string   `json:"user_name,omitempty"`
+	Entries  []Entry  `json:"entries"`
+}
+
+// Entry is one anime or manga on a list
+type Entry struct {
+	ID        int    `json:"id"`
+	Title     string `json:"title"`
+	MediaType string `json:"media_type,omitempty"`
+	// Total is the number of episodes, or chapters for manga; 0 if unknown
+	Total int `json:"total"`
+	// TotalVolumes is the number of volumes of a manga
+	TotalVolumes int `json:"total_volumes,omitempty"`
+
+	// Status is the MAL API status, e.g. plan_to_watch or reading
+	Status string `json:"status"`
+	Score  int    `json:"score"`
+	// Progress is the number of episodes watched, or chapters read
+	Progress    int    `json:"progress"`
+	VolumesRead int    `json:"volumes_read,omitempty"`
+	StartDate   string `json:"start_date,omitempty"`
+	FinishDate  string `json:"finish_date,omitempty"`
+	// Repeating is set while rewatching or rereading
+	Repeating     bool     `json:"repeating,omitempty"`
+	TimesRepeated int      `json:"times_repeated,omitempty"`
+	Priority      int      `json:"priority,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+	Comments      string   `json:"comments,omitempty"`
+}
+
+// Fetch downloads the logged-in user's whole anime or manga list
+func Fetch(ctx context.Context, client *mal.Client, t ListType) (*List, error) {
+	user, err := client.User.MeContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list := &List{Type: t, UserID: user.ID, UserName: user.Name, Entries: []Entry{}}
+
+	switch t {
+	case Anime:
+		opts := mal.AnimeListOptions{Limit: 1000, Fields: []string{mal.AnimeListStatusFields, "media_type"}}
+		for entry, err := range client.List.AnimeListAllContext(ctx, "@me", opts, 0) {
+			if err != nil {
+				return nil, err
+			}
+			list.Entries = append(list.Entries, FromAnime(entry))
+		}
+	case Manga:
+		opts := mal.MangaListOptions{Limit: 1000, Fields: []string{mal.MangaListStatusFields, "media_type"}}
+		for entry, err := range client.List.MangaListAllContext(ctx, "@me", opts, 0) {
+			if err != nil {
+				return nil, err
+			}
+			list.Entries = append(list.Entries, FromManga(entry))
+		}
+	default:
+		return nil, ValidateListType(string(t))
+	}
+	return list, nil
+}
+
+// FromAnime converts an anime list entry
+func FromAnime(entry mal.AnimeListEntry) Entry {
+	status := entry.ListStatus
+	return Entry{
+		ID:            entry.Node.ID,
+		Title:         entry.Node.Title,
+		MediaType:     entry.Node.MediaType,
+		Total:         entry.Node.NumEpisodes,
+		Status:        status.Status,
+		Score:         status.Score,
+		Progress:      status.NumEpisodesWatched,
+		StartDate:     status.StartDate,
+		FinishDate:    status.FinishDate,
+		Repeating:     status.IsRewatching,
+		TimesRepeated: status.NumTimesRewatched,
+		Priority:      status.Priority,
+		Tags:          status.Tags,
+		Comments:      status.Comments,
+	}
+}
+
+// FromManga converts a manga list entry
+func FromManga(entry mal.MangaListEntry) Entry {
+	status := entry.ListStatus
+	return Entry{
+		ID:            entry.Node.ID,
+		Title:         entry.Node.Title,
+		MediaType:     entry.Node.MediaType,
+		Total:         entry.Node.NumChapters,
+		TotalVolumes:  entry.Node.NumVolumes,
+		Status:        status.Status,
+		Score:         status.Score,
+		Progress:      status.NumChaptersRead,
+		VolumesRead:   status.NumVolumesRead,
+		StartDate:     status.StartDate,
+		FinishDate:    status.FinishDate,
+		Repeating:     status.IsRereading,
+		TimesRepeated: status.NumTimesReread,
+		Priority:      status.Priority,
+		Tags:          status.Tags,
+		Comments:      status.Comments,
+	}
+}
+
+// Write writes list to w in format
+func Write(w io.Writer, list *List, format Format) error {
+	switch format {
+	case MALXML:
+		return WriteMALXML(w, list)
+	case CSV:
+		return WriteCSV(w, list)
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(list)
+	}
+	return ValidateFormat(string(format))
+}
+
+// Read reads a list in format from r. Gzip-compressed input, as MAL serves
+// its exports, is decompressed first.
+func Read(r io.Reader, format Format) (*List, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress: %w", err)
+		}
+		defer zr.Close()
+		br = bufio.NewReader(zr)
+	}
+
+	switch format {
+	case MALXML:
+		return ReadMALXML(br)
+	case CSV:
+		return ReadCSV(br)
+	case JSON:
+		var list List
+		if err := json.NewDecoder(br).Decode(&list); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+		if err := ValidateListType(string(list.Type)); err != nil {
+			return nil, err
+		}
+		return &list, nil
+	}
+	return nil, ValidateFormat(string(format))
+}
+
+// DetectFormat guesses a file's format from its name, e.g. animelist.xml.gz,
+// falling back to MAL XML
+func DetectFormat(name string) Format {
+	name = strings.TrimSuffix(strings.ToLower(name), ".gz")
+	switch filepath.Ext(name) {
+	case ".csv":
+		return CSV
+	case ".json":
+		return JSON
+	}
+	return MALXML
+}
@@ -0,0 +1,137 @@
+package backup
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// csvHeader names the CSV columns. The same columns are used for anime
+// and manga; see Entry for what each holds.
+var csvHeader = []string{
+	"type", "id", "title", "media_type", "total", "total_volumes",
+	"status", "score", "progress", "volumes_read", "start_date", "finish_date",
+	"repeating", "times_repeated", "priority", "tags", "comments",
+}
+
+// WriteCSV writes list as CSV with a header row. Tags are joined with
+// commas within their cell.
+func WriteCSV(w io.Writer, list *List) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, e := range list.Entries {
+		record := []string{
+			string(list.Type),
+			strconv.Itoa(e.ID),
+			e.Title,
+			e.MediaType,
+			strconv.Itoa(e.Total),
+			strconv.Itoa(e.TotalVolumes),
+			e.Status,
+			strconv.Itoa(e.Score),
+			strconv.Itoa(e.Progress),
+			strconv.Itoa(e.VolumesRead),
+			e.StartDate,
+			e.FinishDate,
+			strconv.FormatBool(e.Repeating),
+			strconv.Itoa(e.TimesRepeated),
+			strconv.Itoa(e.Priority),
+			strings.Join(e.Tags, ","),
+			e.Comments,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadCSV reads a list written by WriteCSV. Columns are matched by their
+// header, so they may be reordered and only id and status are required.
+// All rows must have the same type; a file without a type column holds
+// anime.
+func ReadCSV(r io.Reader) (*List, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("empty CSV file")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"id", "status"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV file has no %s column", required)
+		}
+	}
+
+	list := &List{Entries: []Entry{}}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CSV: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+		get := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		t := ListType(get("type"))
+		if t == "" {
+			t = Anime
+		}
+		if err := ValidateListType(string(t)); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if list.Type == "" {
+			list.Type = t
+		} else if t != list.Type {
+			return nil, fmt.Errorf("line %d: file holds both anime and manga; import them separately", line)
+		}
+
+		p := fieldParser{get: get}
+		entry := Entry{
+			ID:            p.int("id"),
+			Title:         get("title"),
+			MediaType:     get("media_type"),
+			Total:         p.int("total"),
+			TotalVolumes:  p.int("total_volumes"),
+			Status:        p.status("status", t),
+			Score:         p.int("score"),
+			Progress:      p.int("progress"),
+			VolumesRead:   p.int("volumes_read"),
+			StartDate:     p.date("start_date"),
+			FinishDate:    p.date("finish_date"),
+			Repeating:     p.bool("repeating"),
+			TimesRepeated: p.int("times_repeated"),
+			Priority:      p.priority("priority"),
+			Tags:          splitTags(get("tags")),
+			Comments:      get("comments"),
+		}
+		if err := p.check(entry); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		list.Entries = append(list.Entries, entry)
+	}
+	if list.Type == "" {
+		list.Type = Anime
+	}
+	return list, nil
+}
@@ -0,0 +1,250 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+)
+
+// Strategy decides what happens to imported entries that are already on
+// the list. Entries that are not on the list are always added, and entries
+// missing from the import are never removed.
+type Strategy string
+
+const (
+	// Overwrite replaces list entries with the imported ones
+	Overwrite Strategy = "overwrite"
+	// KeepHigherProgress imports entries like Overwrite but keeps the
+	// status and progress of the list when they are further along
+	KeepHigherProgress Strategy = "keep-higher-progress"
+	// SkipExisting leaves entries already on the list alone
+	SkipExisting Strategy = "skip-existing"
+)
+
+// Strategies are the accepted import strategies, for help text and
+// validation
+var Strategies = []Strategy{Overwrite, KeepHigherProgress, SkipExisting}
+
+// ValidateStrategy checks that s is one of Strategies
+func ValidateStrategy(s string) error {
+	names := make([]string, len(Strategies))
+	for i, strategy := range Strategies {
+		if string(strategy) == s {
+			return nil
+		}
+		names[i] = string(strategy)
+	}
+	return fmt.Errorf("invalid strategy %q, must be one of: %s", s, strings.Join(names, ", "))
+}
+
+// Action is what an import does with one entry
+type Action string
+
+const (
+	Add       Action = "add"
+	Update    Action = "update"
+	Skip      Action = "skip"
+	Unchanged Action = "unchanged"
+)
+
+// FieldChange is one field an import changes, with values formatted for
+// display
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// Change is the planned import of one entry. Entry is the entry as it will
+// be after the import, and Fields lists what differs from the list. Skipped
+// entries list the fields that would have changed.
+type Change struct {
+	Action Action        `json:"action"`
+	Entry  Entry         `json:"entry"`
+	Fields []FieldChange `json:"fields"`
+}
+
+// diffFields are the entry fields an import compares and sets
+var diffFields = []struct {
+	name string
+	get  func(Entry) string
+}{
+	{"status", func(e Entry) string { return e.Status }},
+	{"score", func(e Entry) string { return strconv.Itoa(e.Score) }},
+	{"progress", func(e Entry) string { return strconv.Itoa(e.Progress) }},
+	{"volumes_read", func(e Entry) string { return strconv.Itoa(e.VolumesRead) }},
+	{"start_date", func(e Entry) string { return e.StartDate }},
+	{"finish_date", func(e Entry) string { return e.FinishDate }},
+	{"repeating", func(e Entry) string { return strconv.FormatBool(e.Repeating) }},
+	{"times_repeated", func(e Entry) string { return strconv.Itoa(e.TimesRepeated) }},
+	{"priority", func(e Entry) string { return strconv.Itoa(e.Priority) }},
+	{"tags", func(e Entry) string { return strings.Join(e.Tags, ", ") }},
+	{"comments", func(e Entry) string { return e.Comments }},
+}
+
+// Plan compares imported against the current list and returns a change for
+// every imported entry, in file order. When an ID appears more than once in
+// imported, only its first entry is used.
+func Plan(imported, current *List, strategy Strategy) []Change {
+	existing := make(map[int]Entry, len(current.Entries))
+	for _, e := range current.Entries {
+		existing[e.ID] = e
+	}
+
+	seen := make(map[int]bool, len(imported.Entries))
+	changes := make([]Change, 0, len(imported.Entries))
+	for _, e := range imported.Entries {
+		if seen[e.ID] {
+			continue
+		}
+		seen[e.ID] = true
+
+		old, ok := existing[e.ID]
+		if !ok {
+			changes = append(changes, Change{Action: Add, Entry: e, Fields: diff(Entry{}, e)})
+			continue
+		}
+		target := merge(old, e, strategy)
+		fields := diff(old, target)
+		action := Update
+		switch {
+		case len(fields) == 0:
+			action = Unchanged
+		case strategy == SkipExisting:
+			action = Skip
+		}
+		changes = append(changes, Change{Action: action, Entry: target, Fields: fields})
+	}
+	return changes
+}
+
+// merge returns current updated with imported. Unknown imported dates
+// leave the list's dates alone, since MAL cannot clear them.
+func merge(current, imported Entry, strategy Strategy) Entry {
+	target := current
+	if imported.Title != "" {
+		target.Title = imported.Title
+	}
+	target.Status = imported.Status
+	target.Score = imported.Score
+	target.Progress = imported.Progress
+	target.VolumesRead = imported.VolumesRead
+	target.Repeating = imported.Repeating
+	target.TimesRepeated = imported.TimesRepeated
+	target.Priority = imported.Priority
+	target.Tags = imported.Tags
+	target.Comments = imported.Comments
+	if imported.StartDate != "" {
+		target.StartDate = imported.StartDate
+	}
+	if imported.FinishDate != "" {
+		target.FinishDate = imported.FinishDate
+	}
+
+	if strategy == KeepHigherProgress && furtherAlong(current, imported) {
+		target.Status = current.Status
+		target.Progress = current.Progress
+		target.VolumesRead = current.VolumesRead
+	}
+	return target
+}
+
+// furtherAlong reports whether a has more progress than b, counting a
+// completed entry as ahead of an unfinished one with the same progress
+func furtherAlong(a, b Entry) bool {
+	if a.Progress != b.Progress {
+		return a.Progress > b.Progress
+	}
+	return a.Status == "completed" && b.Status != "completed"
+}
+
+func diff(old, updated Entry) []FieldChange {
+	var fields []FieldChange
+	for _, f := range diffFields {
+		if o, n := f.get(old), f.get(updated); o != n {
+			fields = append(fields, FieldChange{Field: f.name, Old: o, New: n})
+		}
+	}
+	return fields
+}
+
+// Apply makes an add or update change on the logged-in user's list,
+// sending only the changed fields. Other changes are ignored.
+func Apply(ctx context.Context, client *mal.Client, t ListType, change Change) error {
+	if change.Action != Add && change.Action != Update {
+		return nil
+	}
+	e := change.Entry
+	switch t {
+	case Anime:
+		_, err := client.List.UpdateAnimeContext(ctx, e.ID, animeUpdate(e, change.Fields))
+		return err
+	case Manga:
+		_, err := client.List.UpdateMangaContext(ctx, e.ID, mangaUpdate(e, change.Fields))
+		return err
+	}
+	return ValidateListType(string(t))
+}
+
+func animeUpdate(e Entry, fields []FieldChange) mal.AnimeListUpdate {
+	var update mal.AnimeListUpdate
+	for _, f := range fields {
+		switch f.Field {
+		case "status":
+			update.Status = &e.Status
+		case "score":
+			update.Score = &e.Score
+		case "progress":
+			update.NumWatchedEpisodes = &e.Progress
+		case "start_date":
+			update.StartDate = &e.StartDate
+		case "finish_date":
+			update.FinishDate = &e.FinishDate
+		case "repeating":
+			update.IsRewatching = &e.Repeating
+		case "times_repeated":
+			update.NumTimesRewatched = &e.TimesRepeated
+		case "priority":
+			update.Priority = &e.Priority
+		case "tags":
+			update.Tags = &e.Tags
+		case "comments":
+			update.Comments = &e.Comments
+		}
+	}
+	return update
+}
+
+func mangaUpdate(e Entry, fields []FieldChange) mal.MangaListUpdate {
+	var update mal.MangaListUpdate
+	for _, f := range fields {
+		switch f.Field {
+		case "status":
+			update.Status = &e.Status
+		case "score":
+			update.Score = &e.Score
+		case "progress":
+			update.NumChaptersRead = &e.Progress
+		case "volumes_read":
+			update.NumVolumesRead = &e.VolumesRead
+		case "start_date":
+			update.StartDate = &e.StartDate
+		case "finish_date":
+			update.FinishDate = &e.FinishDate
+		case "repeating":
+			update.IsRereading = &e.Repeating
+		case "times_repeated":
+			update.NumTimesReread = &e.TimesRepeated
+		case "priority":
+			update.Priority = &e.Priority
+		case "tags":
+			update.Tags = &e.Tags
+		case "comments":
+			update.Comments = &e.Comments
+		}
+	}
+	return update
+}
@@ -0,0 +1,184 @@
+package backup
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlan(t *testing.T) {
+	onList := Entry{
+		ID: 1, Title: "Sousou no Frieren", Status: "watching", Score: 8, Progress: 10,
+		StartDate: "2024-01-05", Tags: []string{"fantasy"},
+	}
+	behind := Entry{
+		ID: 1, Title: "Sousou no Frieren", Status: "watching", Score: 9, Progress: 4,
+		StartDate: "2023-12-01", Comments: "from the old tracker",
+	}
+	ahead := Entry{
+		ID: 1, Title: "Sousou no Frieren", Status: "completed", Score: 10, Progress: 28,
+		StartDate: "2024-01-05", FinishDate: "2024-03-22", Tags: []string{"fantasy"},
+	}
+
+	tests := []struct {
+		name      string
+		current   []Entry
+		imported  Entry
+		strategy  Strategy
+		want      Action
+		wantEntry Entry
+		// wantFields are the names of the changed fields
+		wantFields []string
+	}{
+		{
+			name:       "new entry is added",
+			imported:   ahead,
+			strategy:   SkipExisting,
+			want:       Add,
+			wantEntry:  ahead,
+			wantFields: []string{"status", "score", "progress", "start_date", "finish_date", "tags"},
+		},
+		{
+			name:       "overwrite replaces progress",
+			current:    []Entry{onList},
+			imported:   behind,
+			strategy:   Overwrite,
+			want:       Update,
+			wantEntry:  behind,
+			wantFields: []string{"score", "progress", "start_date", "tags", "comments"},
+		},
+		{
+			name:     "keep-higher-progress keeps the list's progress",
+			current:  []Entry{onList},
+			imported: behind,
+			strategy: KeepHigherProgress,
+			want:     Update,
+			wantEntry: Entry{
+				ID: 1, Title: "Sousou no Frieren", Status: "watching", Score: 9, Progress: 10,
+				StartDate: "2023-12-01", Comments: "from the old tracker",
+			},
+			wantFields: []string{"score", "start_date", "tags", "comments"},
+		},
+		{
+			name:       "keep-higher-progress takes higher imported progress",
+			current:    []Entry{onList},
+			imported:   ahead,
+			strategy:   KeepHigherProgress,
+			want:       Update,
+			wantEntry:  ahead,
+			wantFields: []string{"status", "score", "progress", "finish_date"},
+		},
+		{
+			name:    "keep-higher-progress counts completed as ahead",
+			current: []Entry{{ID: 1, Status: "completed", Progress: 12}},
+			imported: Entry{
+				ID: 1, Status: "watching", Progress: 12, Score: 7,
+			},
+			strategy:   KeepHigherProgress,
+			want:       Update,
+			wantEntry:  Entry{ID: 1, Status: "completed", Progress: 12, Score: 7},
+			wantFields: []string{"score"},
+		},
+		{
+			name:       "skip-existing leaves the entry alone",
+			current:    []Entry{onList},
+			imported:   behind,
+			strategy:   SkipExisting,
+			want:       Skip,
+			wantEntry:  behind,
+			wantFields: []string{"score", "progress", "start_date", "tags", "comments"},
+		},
+		{
+			name:      "identical entry is unchanged",
+			current:   []Entry{onList},
+			imported:  onList,
+			strategy:  Overwrite,
+			want:      Unchanged,
+			wantEntry: onList,
+		},
+		{
+			name:    "empty imported dates keep the list's dates",
+			current: []Entry{ahead},
+			imported: Entry{
+				ID: 1, Title: "Sousou no Frieren", Status: "completed", Score: 10, Progress: 28,
+				Tags: []string{"fantasy"},
+			},
+			strategy:  Overwrite,
+			want:      Unchanged,
+			wantEntry: ahead,
+		},
+		{
+			name:    "empty imported title keeps the list's title",
+			current: []Entry{onList},
+			imported: Entry{
+				ID: 1, Status: "watching", Score: 8, Progress: 11,
+				StartDate: "2024-01-05", Tags: []string{"fantasy"},
+			},
+			strategy: Overwrite,
+			want:     Update,
+			wantEntry: Entry{
+				ID: 1, Title: "Sousou no Frieren", Status: "watching", Score: 8, Progress: 11,
+				StartDate: "2024-01-05", Tags: []string{"fantasy"},
+			},
+			wantFields: []string{"progress"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := &List{Type: Anime, Entries: tt.current}
+			imported := &List{Type: Anime, Entries: []Entry{tt.imported}}
+
+			changes := Plan(imported, current, tt.strategy)
+			if len(changes) != 1 {
+				t.Fatalf("got %d changes, want 1", len(changes))
+			}
+			change := changes[0]
+			if change.Action != tt.want {
+				t.Errorf("action = %s, want %s", change.Action, tt.want)
+			}
+			if !reflect.DeepEqual(change.Entry, tt.wantEntry) {
+				t.Errorf("entry:\n got %+v\nwant %+v", change.Entry, tt.wantEntry)
+			}
+			var fields []string
+			for _, f := range change.Fields {
+				fields = append(fields, f.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("changed fields = %q, want %q", fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestPlanDuplicateIDs(t *testing.T) {
+	imported := &List{Type: Anime, Entries: []Entry{
+		{ID: 1, Status: "completed", Progress: 12},
+		{ID: 2, Status: "plan_to_watch"},
+		{ID: 1, Status: "dropped", Progress: 3},
+	}}
+	current := &List{Type: Anime, Entries: []Entry{{ID: 1, Status: "watching", Progress: 5}}}
+
+	changes := Plan(imported, current, Overwrite)
+	if len(changes) != 2 {
+		t.Fatalf("got %d changes, want one per distinct ID", len(changes))
+	}
+	if changes[0].Entry.ID != 1 || changes[0].Entry.Status != "completed" {
+		t.Errorf("first change = %+v, want the first entry for ID 1", changes[0].Entry)
+	}
+	if changes[1].Entry.ID != 2 || changes[1].Action != Add {
+		t.Errorf("second change = %s %+v, want ID 2 added", changes[1].Action, changes[1].Entry)
+	}
+}
+
+func TestAnimeUpdateSendsChangedFields(t *testing.T) {
+	e := Entry{ID: 1, Status: "completed", Score: 9, Progress: 12, FinishDate: "2024-03-22"}
+	update := animeUpdate(e, []FieldChange{{Field: "progress"}, {Field: "finish_date"}})
+	if update.NumWatchedEpisodes == nil || *update.NumWatchedEpisodes != 12 {
+		t.Errorf("progress not sent: %+v", update)
+	}
+	if update.FinishDate == nil || *update.FinishDate != "2024-03-22" {
+		t.Errorf("finish date not sent: %+v", update)
+	}
+	if update.Status != nil || update.Score != nil {
+		t.Errorf("unchanged fields sent: %+v", update)
+	}
+}
@@ -0,0 +1,106 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Journal records which entries of an import have been applied, so an
+// interrupted import continues where it stopped instead of sending every
+// update again. It is an append-only file of entry IDs, one per line,
+// synced after each entry.
+type Journal struct {
+	path string
+	file *os.File
+	done map[int]bool
+}
+
+// JournalPath returns where the journal for importing data with strategy
+// is kept: a file in the user's cache directory named after a hash of
+// both, so rerunning the same import finds it
+func JournalPath(data []byte, strategy Strategy) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find cache directory: %w", err)
+	}
+	sum := sha256.Sum256(append([]byte(strategy+"\n"), data...))
+	return filepath.Join(dir, "zutto", "imports", hex.EncodeToString(sum[:8])+".journal"), nil
+}
+
+// OpenJournal opens the journal at path, creating it if needed, and reads
+// the entries already applied
+func OpenJournal(path string) (*Journal, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	// A last line without its newline was cut short by a crash; it may
+	// hold part of an ID, so it is dropped before anything is appended
+	complete := data[:bytes.LastIndexByte(data, '\n')+1]
+	if len(complete) < len(data) {
+		if err := file.Truncate(int64(len(complete))); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to repair journal: %w", err)
+		}
+	}
+
+	j := &Journal{path: path, file: file, done: make(map[int]bool)}
+	for _, line := range strings.Split(string(complete), "\n") {
+		if id, err := strconv.Atoi(strings.TrimSpace(line)); err == nil {
+			j.done[id] = true
+		}
+	}
+	return j, nil
+}
+
+// Len returns the number of entries applied so far
+func (j *Journal) Len() int {
+	return len(j.done)
+}
+
+// Done reports whether the entry with id has been applied
+func (j *Journal) Done(id int) bool {
+	return j.done[id]
+}
+
+// Record marks the entry with id as applied
+func (j *Journal) Record(id int) error {
+	if _, err := fmt.Fprintf(j.file, "%d\n", id); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	if err := j.file.Sync(); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	j.done[id] = true
+	return nil
+}
+
+// Close closes the journal, keeping it for a later run
+func (j *Journal) Close() error {
+	return j.file.Close()
+}
+
+// Remove closes and deletes the journal once the import is complete
+func (j *Journal) Remove() error {
+	j.file.Close()
+	if err := os.Remove(j.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove journal: %w", err)
+	}
+	return nil
+}
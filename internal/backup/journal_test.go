@@ -0,0 +1,112 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJournalResumes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "imports", "test.journal")
+
+	j, err := OpenJournal(path)
+	if err != nil {
+		t.Fatalf("OpenJournal: %v", err)
+	}
+	if j.Len() != 0 {
+		t.Errorf("new journal has %d entries", j.Len())
+	}
+	for _, id := range []int{52991, 9253} {
+		if err := j.Record(id); err != nil {
+			t.Fatalf("Record(%d): %v", id, err)
+		}
+	}
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("journal mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	// A later run picks up where this one stopped
+	j, err = OpenJournal(path)
+	if err != nil {
+		t.Fatalf("OpenJournal: %v", err)
+	}
+	if j.Len() != 2 || !j.Done(52991) || !j.Done(9253) || j.Done(1) {
+		t.Errorf("reopened journal has %d entries, want 52991 and 9253", j.Len())
+	}
+	if err := j.Record(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Remove(); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("journal still exists after Remove: %v", err)
+	}
+}
+
+func TestJournalTruncatedLastLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		done     []int
+		notDone  []int
+	}{
+		// 9253 was being written when the import was killed
+		{"cut short", "52991\n925", []int{52991}, []int{925, 9253}},
+		{"only a partial line", "52", nil, []int{52, 52991}},
+		{"garbage line", "52991\nnot an id\n9253\n", []int{52991, 9253}, nil},
+		{"empty", "", nil, []int{52991}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "test.journal")
+			if err := os.WriteFile(path, []byte(tt.contents), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			j, err := OpenJournal(path)
+			if err != nil {
+				t.Fatalf("OpenJournal: %v", err)
+			}
+			for _, id := range tt.done {
+				if !j.Done(id) {
+					t.Errorf("%d is not done", id)
+				}
+			}
+			for _, id := range tt.notDone {
+				if j.Done(id) {
+					t.Errorf("%d is done", id)
+				}
+			}
+			if j.Len() != len(tt.done) {
+				t.Errorf("Len = %d, want %d", j.Len(), len(tt.done))
+			}
+
+			// Entries recorded after reopening are read back whole
+			if err := j.Record(9253); err != nil {
+				t.Fatal(err)
+			}
+			j.Close()
+			j, err = OpenJournal(path)
+			if err != nil {
+				t.Fatalf("OpenJournal: %v", err)
+			}
+			defer j.Close()
+			if !j.Done(9253) {
+				t.Errorf("9253 recorded after the partial line was not read back")
+			}
+			for _, id := range tt.done {
+				if !j.Done(id) {
+					t.Errorf("%d is no longer done after reopening", id)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,398 @@
+package backup
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MAL's export marks anime and manga lists with these user_export_type values
+const (
+	animeExportType = 1
+	mangaExportType = 2
+)
+
+// cdata is text written as a CDATA section, as MAL does for free text
+type cdata struct {
+	Text string `xml:",cdata"`
+}
+
+type xmlAnimeExport struct {
+	XMLName xml.Name      `xml:"myanimelist"`
+	Info    xmlAnimeInfo  `xml:"myinfo"`
+	Entries []xmlAnimeRow `xml:"anime"`
+}
+
+type xmlAnimeInfo struct {
+	UserID           int    `xml:"user_id"`
+	UserName         string `xml:"user_name"`
+	ExportType       int    `xml:"user_export_type"`
+	Total            int    `xml:"user_total_anime"`
+	TotalWatching    int    `xml:"user_total_watching"`
+	TotalCompleted   int    `xml:"user_total_completed"`
+	TotalOnHold      int    `xml:"user_total_onhold"`
+	TotalDropped     int    `xml:"user_total_dropped"`
+	TotalPlanToWatch int    `xml:"user_total_plantowatch"`
+}
+
+type xmlAnimeRow struct {
+	ID             int    `xml:"series_animedb_id"`
+	Title          cdata  `xml:"series_title"`
+	Type           string `xml:"series_type"`
+	Episodes       int    `xml:"series_episodes"`
+	MyID           int    `xml:"my_id"`
+	Watched        int    `xml:"my_watched_episodes"`
+	StartDate      string `xml:"my_start_date"`
+	FinishDate     string `xml:"my_finish_date"`
+	Rated          string `xml:"my_rated"`
+	Score          int    `xml:"my_score"`
+	Storage        string `xml:"my_storage"`
+	StorageValue   string `xml:"my_storage_value"`
+	Status         string `xml:"my_status"`
+	Comments       cdata  `xml:"my_comments"`
+	TimesWatched   int    `xml:"my_times_watched"`
+	RewatchValue   string `xml:"my_rewatch_value"`
+	Priority       string `xml:"my_priority"`
+	Tags           cdata  `xml:"my_tags"`
+	Rewatching     int    `xml:"my_rewatching"`
+	RewatchingEp   int    `xml:"my_rewatching_ep"`
+	Discuss        int    `xml:"my_discuss"`
+	SNS            string `xml:"my_sns"`
+	UpdateOnImport int    `xml:"update_on_import"`
+}
+
+type xmlMangaExport struct {
+	XMLName xml.Name      `xml:"myanimelist"`
+	Info    xmlMangaInfo  `xml:"myinfo"`
+	Entries []xmlMangaRow `xml:"manga"`
+}
+
+type xmlMangaInfo struct {
+	UserID          int    `xml:"user_id"`
+	UserName        string `xml:"user_name"`
+	ExportType      int    `xml:"user_export_type"`
+	Total           int    `xml:"user_total_manga"`
+	TotalReading    int    `xml:"user_total_reading"`
+	TotalCompleted  int    `xml:"user_total_completed"`
+	TotalOnHold     int    `xml:"user_total_onhold"`
+	TotalDropped    int    `xml:"user_total_dropped"`
+	TotalPlanToRead int    `xml:"user_total_plantoread"`
+}
+
+type xmlMangaRow struct {
+	ID              int    `xml:"manga_mangadb_id"`
+	Title           cdata  `xml:"manga_title"`
+	Volumes         int    `xml:"manga_volumes"`
+	Chapters        int    `xml:"manga_chapters"`
+	MyID            int    `xml:"my_id"`
+	ReadVolumes     int    `xml:"my_read_volumes"`
+	ReadChapters    int    `xml:"my_read_chapters"`
+	StartDate       string `xml:"my_start_date"`
+	FinishDate      string `xml:"my_finish_date"`
+	ScanlationGroup cdata  `xml:"my_scanalation_group"`
+	Score           int    `xml:"my_score"`
+	Storage         string `xml:"my_storage"`
+	RetailVolumes   int    `xml:"my_retail_volumes"`
+	Status          string `xml:"my_status"`
+	Comments        cdata  `xml:"my_comments"`
+	TimesRead       int    `xml:"my_times_read"`
+	Tags            cdata  `xml:"my_tags"`
+	Priority        string `xml:"my_priority"`
+	RereadValue     string `xml:"my_reread_value"`
+	Rereading       string `xml:"my_rereading"`
+	Discuss         string `xml:"my_discuss"`
+	SNS             string `xml:"my_sns"`
+	UpdateOnImport  int    `xml:"update_on_import"`
+}
+
+// xmlStatuses maps API statuses to the names in MAL's export
+var xmlStatuses = map[string]string{
+	"watching":      "Watching",
+	"reading":       "Reading",
+	"completed":     "Completed",
+	"on_hold":       "On-Hold",
+	"dropped":       "Dropped",
+	"plan_to_watch": "Plan to Watch",
+	"plan_to_read":  "Plan to Read",
+}
+
+// xmlMediaTypes maps API media types to the names in MAL's export
+var xmlMediaTypes = map[string]string{
+	"tv":         "TV",
+	"ova":        "OVA",
+	"movie":      "Movie",
+	"special":    "Special",
+	"ona":        "ONA",
+	"music":      "Music",
+	"tv_special": "TV Special",
+	"cm":         "CM",
+	"pv":         "PV",
+	"unknown":    "Unknown",
+}
+
+// xmlPriorities are the names MAL's export uses for priorities 0 to 2
+var xmlPriorities = []string{"LOW", "MEDIUM", "HIGH"}
+
+// WriteMALXML writes list in MAL's XML export format, which MAL and most
+// other trackers can import. Every entry is marked to update existing
+// entries on import.
+func WriteMALXML(w io.Writer, list *List) error {
+	var export any
+	switch list.Type {
+	case Manga:
+		export = mangaExport(list)
+	case Anime:
+		export = animeExport(list)
+	default:
+		return ValidateListType(string(list.Type))
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "\t")
+	if err := enc.Encode(export); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func animeExport(list *List) *xmlAnimeExport {
+	export := &xmlAnimeExport{Info: xmlAnimeInfo{
+		UserID:     list.UserID,
+		UserName:   list.UserName,
+		ExportType: animeExportType,
+		Total:      len(list.Entries),
+	}}
+	for _, e := range list.Entries {
+		switch e.Status {
+		case "watching":
+			export.Info.TotalWatching++
+		case "completed":
+			export.Info.TotalCompleted++
+		case "on_hold":
+			export.Info.TotalOnHold++
+		case "dropped":
+			export.Info.TotalDropped++
+		case "plan_to_watch":
+			export.Info.TotalPlanToWatch++
+		}
+
+		export.Entries = append(export.Entries, xmlAnimeRow{
+			ID:             e.ID,
+			Title:          cdata{e.Title},
+			Type:           xmlName(xmlMediaTypes, e.MediaType),
+			Episodes:       e.Total,
+			Watched:        e.Progress,
+			StartDate:      xmlDate(e.StartDate),
+			FinishDate:     xmlDate(e.FinishDate),
+			Score:          e.Score,
+			StorageValue:   "0.00",
+			Status:         xmlName(xmlStatuses, e.Status),
+			Comments:       cdata{e.Comments},
+			TimesWatched:   e.TimesRepeated,
+			Priority:       xmlPriority(e.Priority),
+			Tags:           cdata{strings.Join(e.Tags, ", ")},
+			Rewatching:     boolInt(e.Repeating),
+			Discuss:        1,
+			SNS:            "default",
+			UpdateOnImport: 1,
+		})
+	}
+	return export
+}
+
+func mangaExport(list *List) *xmlMangaExport {
+	export := &xmlMangaExport{Info: xmlMangaInfo{
+		UserID:     list.UserID,
+		UserName:   list.UserName,
+		ExportType: mangaExportType,
+		Total:      len(list.Entries),
+	}}
+	for _, e := range list.Entries {
+		switch e.Status {
+		case "reading":
+			export.Info.TotalReading++
+		case "completed":
+			export.Info.TotalCompleted++
+		case "on_hold":
+			export.Info.TotalOnHold++
+		case "dropped":
+			export.Info.TotalDropped++
+		case "plan_to_read":
+			export.Info.TotalPlanToRead++
+		}
+
+		rereading := "NO"
+		if e.Repeating {
+			rereading = "YES"
+		}
+		export.Entries = append(export.Entries, xmlMangaRow{
+			ID:             e.ID,
+			Title:          cdata{e.Title},
+			Volumes:        e.TotalVolumes,
+			Chapters:       e.Total,
+			ReadVolumes:    e.VolumesRead,
+			ReadChapters:   e.Progress,
+			StartDate:      xmlDate(e.StartDate),
+			FinishDate:     xmlDate(e.FinishDate),
+			Score:          e.Score,
+			Status:         xmlName(xmlStatuses, e.Status),
+			Comments:       cdata{e.Comments},
+			TimesRead:      e.TimesRepeated,
+			Tags:           cdata{strings.Join(e.Tags, ", ")},
+			Priority:       xmlPriority(e.Priority),
+			Rereading:      rereading,
+			Discuss:        "YES",
+			SNS:            "default",
+			UpdateOnImport: 1,
+		})
+	}
+	return export
+}
+
+// xmlName looks up value in names, keeping unknown values as they are
+func xmlName(names map[string]string, value string) string {
+	if name, ok := names[value]; ok {
+		return name
+	}
+	return value
+}
+
+// xmlDate writes dates as MAL does, with zeros for unknown parts:
+// "2021-03" becomes "2021-03-00" and "" becomes "0000-00-00"
+func xmlDate(date string) string {
+	const zero = "0000-00-00"
+	if len(date) >= len(zero) {
+		return date
+	}
+	return date + zero[len(date):]
+}
+
+func xmlPriority(priority int) string {
+	if priority < 0 || priority >= len(xmlPriorities) {
+		return strconv.Itoa(priority)
+	}
+	return xmlPriorities[priority]
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// xmlImport is a MAL export read for import. Rows are kept as raw fields
+// since exports from MAL and other trackers differ in the details.
+type xmlImport struct {
+	Info  xmlFields   `xml:"myinfo"`
+	Anime []xmlFields `xml:"anime"`
+	Manga []xmlFields `xml:"manga"`
+}
+
+type xmlFields struct {
+	Fields []xmlField `xml:",any"`
+}
+
+type xmlField struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+func (f xmlFields) get(name string) string {
+	for _, field := range f.Fields {
+		if field.XMLName.Local == name {
+			return strings.TrimSpace(field.Value)
+		}
+	}
+	return ""
+}
+
+// ReadMALXML reads a list in MAL's XML export format. Whether it holds
+// anime or manga is taken from its entries, or from user_export_type for
+// an empty list.
+func ReadMALXML(r io.Reader) (*List, error) {
+	var doc xmlImport
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse XML: %w", err)
+	}
+	if len(doc.Anime) > 0 && len(doc.Manga) > 0 {
+		return nil, errors.New("file holds both anime and manga; import them separately")
+	}
+
+	list := &List{UserName: doc.Info.get("user_name"), Entries: []Entry{}}
+	list.UserID, _ = strconv.Atoi(doc.Info.get("user_id"))
+	exportType := doc.Info.get("user_export_type")
+	switch {
+	case len(doc.Anime) > 0 || exportType == strconv.Itoa(animeExportType):
+		list.Type = Anime
+		for i, row := range doc.Anime {
+			entry, err := animeFromXML(row)
+			if err != nil {
+				return nil, fmt.Errorf("anime %d: %w", i+1, err)
+			}
+			list.Entries = append(list.Entries, entry)
+		}
+	case len(doc.Manga) > 0 || exportType == strconv.Itoa(mangaExportType):
+		list.Type = Manga
+		for i, row := range doc.Manga {
+			entry, err := mangaFromXML(row)
+			if err != nil {
+				return nil, fmt.Errorf("manga %d: %w", i+1, err)
+			}
+			list.Entries = append(list.Entries, entry)
+		}
+	default:
+		return nil, errors.New("not a MyAnimeList export: no anime or manga entries")
+	}
+	return list, nil
+}
+
+func animeFromXML(row xmlFields) (Entry, error) {
+	p := fieldParser{get: row.get}
+	entry := Entry{
+		ID:            p.int("series_animedb_id"),
+		Title:         row.get("series_title"),
+		MediaType:     p.mediaType("series_type"),
+		Total:         p.int("series_episodes"),
+		Status:        p.status("my_status", Anime),
+		Score:         p.int("my_score"),
+		Progress:      p.int("my_watched_episodes"),
+		StartDate:     p.date("my_start_date"),
+		FinishDate:    p.date("my_finish_date"),
+		Repeating:     p.bool("my_rewatching"),
+		TimesRepeated: p.int("my_times_watched"),
+		Priority:      p.priority("my_priority"),
+		Tags:          splitTags(row.get("my_tags")),
+		Comments:      row.get("my_comments"),
+	}
+	return entry, p.check(entry)
+}
+
+func mangaFromXML(row xmlFields) (Entry, error) {
+	p := fieldParser{get: row.get}
+	entry := Entry{
+		ID:            p.int("manga_mangadb_id"),
+		Title:         row.get("manga_title"),
+		Total:         p.int("manga_chapters"),
+		TotalVolumes:  p.int("manga_volumes"),
+		Status:        p.status("my_status", Manga),
+		Score:         p.int("my_score"),
+		Progress:      p.int("my_read_chapters"),
+		VolumesRead:   p.int("my_read_volumes"),
+		StartDate:     p.date("my_start_date"),
+		FinishDate:    p.date("my_finish_date"),
+		Repeating:     p.bool("my_rereading"),
+		TimesRepeated: p.int("my_times_read"),
+		Priority:      p.priority("my_priority"),
+		Tags:          splitTags(row.get("my_tags")),
+		Comments:      row.get("my_comments"),
+	}
+	return entry, p.check(entry)
+}
@@ -0,0 +1,135 @@
+package backup
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMALXMLRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		list *List
+		// want are fragments the export must contain
+		want []string
+	}{
+		{
+			name: "anime",
+			list: &List{Type: Anime, UserID: 42, UserName: "someone", Entries: []Entry{
+				{
+					ID: 52991, Title: "Sousou no Frieren", MediaType: "tv", Total: 28,
+					Status: "completed", Score: 10, Progress: 28,
+					StartDate: "2023-09-29", FinishDate: "2024-03",
+					TimesRepeated: 1, Priority: 2,
+					Tags:     []string{"fantasy", "favorites"},
+					Comments: `Rewatch <soon> & often, "really"`,
+				},
+				{ID: 9253, Title: "Steins;Gate", MediaType: "tv", Total: 24, Status: "watching", Progress: 12, StartDate: "2024"},
+				{ID: 5114, Title: "Fullmetal Alchemist: Brotherhood", MediaType: "tv", Total: 64, Status: "on_hold", Progress: 30, Repeating: true},
+				{ID: 33352, Title: "Violet Evergarden: Kitto \"Ai\" wo Shiru Hi ga Kuru no Darou", MediaType: "tv_special", Total: 1, Status: "dropped"},
+				{ID: 1, Title: "Cowboy Bebop", MediaType: "tv", Total: 26, Status: "plan_to_watch", Comments: "ends with ]]> in it"},
+			}},
+			want: []string{
+				"<user_export_type>1</user_export_type>",
+				"<user_total_anime>5</user_total_anime>",
+				"<my_status>Completed</my_status>",
+				"<my_status>Watching</my_status>",
+				"<my_status>On-Hold</my_status>",
+				"<my_status>Dropped</my_status>",
+				"<my_status>Plan to Watch</my_status>",
+				"<my_watched_episodes>28</my_watched_episodes>",
+				"<my_start_date>2023-09-29</my_start_date>",
+				"<my_finish_date>2024-03-00</my_finish_date>",
+				"<my_start_date>2024-00-00</my_start_date>",
+				"<my_start_date>0000-00-00</my_start_date>",
+				"<series_type>TV Special</series_type>",
+				"<my_priority>HIGH</my_priority>",
+				"<series_title><![CDATA[Sousou no Frieren]]></series_title>",
+				`<my_comments><![CDATA[Rewatch <soon> & often, "really"]]></my_comments>`,
+				"<my_tags><![CDATA[fantasy, favorites]]></my_tags>",
+				"<update_on_import>1</update_on_import>",
+			},
+		},
+		{
+			name: "manga",
+			list: &List{Type: Manga, UserID: 42, UserName: "someone", Entries: []Entry{
+				{
+					ID: 2, Title: "Berserk", Total: 0, TotalVolumes: 0,
+					Status: "reading", Score: 10, Progress: 374, VolumesRead: 41,
+					StartDate: "2019-05-01", Priority: 1,
+					Tags: []string{"dark fantasy"}, Comments: "Guts & Griffith",
+				},
+				{ID: 1706, Title: "JoJo no Kimyou na Bouken Part 7: Steel Ball Run", Total: 96, TotalVolumes: 24, Status: "completed", Progress: 96, VolumesRead: 24, FinishDate: "2020-01", Repeating: true, TimesRepeated: 2},
+				{ID: 13, Title: "One Piece", Status: "on_hold", Progress: 1000},
+				{ID: 21, Title: "Death Note", Total: 108, TotalVolumes: 12, Status: "dropped", Progress: 20},
+				{ID: 642, Title: "Vagabond", Status: "plan_to_read"},
+			}},
+			want: []string{
+				"<user_export_type>2</user_export_type>",
+				"<user_total_manga>5</user_total_manga>",
+				"<my_status>Reading</my_status>",
+				"<my_status>Completed</my_status>",
+				"<my_status>On-Hold</my_status>",
+				"<my_status>Dropped</my_status>",
+				"<my_status>Plan to Read</my_status>",
+				"<my_read_chapters>374</my_read_chapters>",
+				"<my_read_volumes>41</my_read_volumes>",
+				"<my_finish_date>2020-01-00</my_finish_date>",
+				"<my_start_date>0000-00-00</my_start_date>",
+				"<my_rereading>YES</my_rereading>",
+				"<my_priority>MEDIUM</my_priority>",
+				"<manga_title><![CDATA[Berserk]]></manga_title>",
+				"<my_comments><![CDATA[Guts & Griffith]]></my_comments>",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, tt.list, MALXML); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			export := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(export, want) {
+					t.Errorf("export is missing %s", want)
+				}
+			}
+			if t.Failed() {
+				t.Logf("export:\n%s", export)
+			}
+
+			got, err := Read(&buf, MALXML)
+			if err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			if got.Type != tt.list.Type || got.UserID != tt.list.UserID || got.UserName != tt.list.UserName {
+				t.Errorf("read %s list of %d %q, want %s list of %d %q",
+					got.Type, got.UserID, got.UserName, tt.list.Type, tt.list.UserID, tt.list.UserName)
+			}
+			if len(got.Entries) != len(tt.list.Entries) {
+				t.Fatalf("read %d entries, want %d", len(got.Entries), len(tt.list.Entries))
+			}
+			for i := range got.Entries {
+				if !reflect.DeepEqual(got.Entries[i], tt.list.Entries[i]) {
+					t.Errorf("entry %d:\n got %+v\nwant %+v", i, got.Entries[i], tt.list.Entries[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReadMALXMLEmptyList(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteMALXML(&buf, &List{Type: Manga, UserName: "someone"}); err != nil {
+		t.Fatalf("WriteMALXML: %v", err)
+	}
+	list, err := ReadMALXML(&buf)
+	if err != nil {
+		t.Fatalf("ReadMALXML: %v", err)
+	}
+	if list.Type != Manga || len(list.Entries) != 0 {
+		t.Errorf("read %s list with %d entries, want an empty manga list", list.Type, len(list.Entries))
+	}
+}
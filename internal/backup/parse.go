@@ -0,0 +1,150 @@
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// fieldParser reads typed entry fields from a file's text values, keeping
+// the first error so a row can be parsed in one expression and checked once
+type fieldParser struct {
+	get func(name string) string
+	err error
+}
+
+func (p *fieldParser) fail(name, value string) {
+	if p.err == nil {
+		p.err = fmt.Errorf("invalid %s %q", name, value)
+	}
+}
+
+func (p *fieldParser) int(name string) int {
+	value := p.get(name)
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		p.fail(name, value)
+	}
+	return n
+}
+
+// bool accepts the 1/0 and YES/NO of MAL's exports as well as true/false
+func (p *fieldParser) bool(name string) bool {
+	switch value := strings.ToLower(p.get(name)); value {
+	case "1", "yes", "true":
+		return true
+	case "", "0", "no", "false":
+		return false
+	default:
+		p.fail(name, value)
+		return false
+	}
+}
+
+// status accepts MAL's export names ("Plan to Watch"), its older numeric
+// codes and API statuses, and returns the API status for list type t
+func (p *fieldParser) status(name string, t ListType) string {
+	value := p.get(name)
+	status, ok := importStatuses[strings.ToLower(value)]
+	if !ok {
+		if value != "" {
+			p.fail(name, value)
+		}
+		return ""
+	}
+	if manga, ok := mangaStatuses[status]; ok && t == Manga {
+		return manga
+	}
+	return status
+}
+
+// priority accepts LOW, MEDIUM and HIGH or 0 to 2
+func (p *fieldParser) priority(name string) int {
+	value := p.get(name)
+	for i, priority := range xmlPriorities {
+		if strings.EqualFold(value, priority) {
+			return i
+		}
+	}
+	return p.int(name)
+}
+
+// mediaType turns an export name like "TV Special" into the API's tv_special
+func (p *fieldParser) mediaType(name string) string {
+	value := p.get(name)
+	for mediaType, exportName := range xmlMediaTypes {
+		if strings.EqualFold(value, exportName) {
+			return mediaType
+		}
+	}
+	return strings.ReplaceAll(strings.ToLower(value), " ", "_")
+}
+
+// date undoes xmlDate, dropping unknown parts: "2021-03-00" becomes
+// "2021-03" and "0000-00-00" becomes ""
+func (p *fieldParser) date(name string) string {
+	value := p.get(name)
+	for strings.HasSuffix(value, "-00") {
+		value = strings.TrimSuffix(value, "-00")
+	}
+	if value == "0000" {
+		return ""
+	}
+	return value
+}
+
+// check returns the first parse error, or an error if entry lacks the
+// fields an import needs
+func (p *fieldParser) check(entry Entry) error {
+	if p.err != nil {
+		return p.err
+	}
+	if entry.ID <= 0 {
+		return errors.New("missing id")
+	}
+	if entry.Status == "" {
+		return fmt.Errorf("missing status for %d", entry.ID)
+	}
+	return nil
+}
+
+// importStatuses maps the status names found in export files to API
+// statuses. Manga statuses are derived from the anime ones.
+var importStatuses = map[string]string{
+	"1":             "watching",
+	"2":             "completed",
+	"3":             "on_hold",
+	"4":             "dropped",
+	"6":             "plan_to_watch",
+	"watching":      "watching",
+	"reading":       "watching",
+	"completed":     "completed",
+	"on-hold":       "on_hold",
+	"on_hold":       "on_hold",
+	"dropped":       "dropped",
+	"plan to watch": "plan_to_watch",
+	"plan to read":  "plan_to_watch",
+	"plan_to_watch": "plan_to_watch",
+	"plan_to_read":  "plan_to_watch",
+}
+
+// mangaStatuses are the manga names of the anime statuses that differ
+var mangaStatuses = map[string]string{
+	"watching":      "reading",
+	"plan_to_watch": "plan_to_read",
+}
+
+// splitTags splits a comma-separated tag list, dropping empty tags
+func splitTags(s string) []string {
+	var tags []string
+	for _, tag := range strings.Split(s, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,202 @@
+// Package config reads and writes zutto's config file.
+//
+// The file is YAML. Top-level settings apply to every run; a named profile
+// under "profiles" overrides them when selected:
+//
+//	client_id: 0123456789abcdef
+//	output: text
+//	limits:
+//	  search: 20
+//	profiles:
+//	  work:
+//	    output: json
+//	    nsfw: true
+//
+// Environment variables take precedence over the file, and command-line
+// flags over both.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Settings are the values a config file or profile can set. Zero values
+// mean unset.
+type Settings struct {
+	ClientID      string `yaml:"client_id,omitempty"`
+	Output        string `yaml:"output,omitempty"`
+	TitleLanguage string `yaml:"title_language,omitempty"`
+	NSFW          *bool  `yaml:"nsfw,omitempty"`
+	Limits        Limits `yaml:"limits,omitempty"`
+	Cache         Cache  `yaml:"cache,omitempty"`
+}
+
+// Limits are default result counts for commands with a --limit flag
+type Limits struct {
+	Search  int `yaml:"search,omitempty"`
+	Ranking int `yaml:"ranking,omitempty"`
+	Season  int `yaml:"season,omitempty"`
+	List    int `yaml:"list,omitempty"`
+}
+
+// Cache holds response cache lifetimes, e.g. 12h
+type Cache struct {
+	DetailsTTL  time.Duration `yaml:"details_ttl,omitempty"`
+	SearchTTL   time.Duration `yaml:"search_ttl,omitempty"`
+	RankingsTTL time.Duration `yaml:"rankings_ttl,omitempty"`
+	SeasonalTTL time.Duration `yaml:"seasonal_ttl,omitempty"`
+}
+
+// File is the contents of a config file
+type File struct {
+	Settings `yaml:",inline"`
+	Profiles map[string]*Settings `yaml:"profiles,omitempty"`
+}
+
+// DefaultPath returns config.yaml under the user's config directory
+// ($XDG_CONFIG_HOME/zutto on Linux)
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "zutto", "config.yaml"), nil
+}
+
+// Load reads a config file. A missing file is treated as empty.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &File{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return &file, nil
+}
+
+// Save writes the config file, creating its directory if needed
+func (f *File) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(f); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// ProfileNames lists the profiles defined in the file
+func (f *File) ProfileNames() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// profile returns the named profile's settings, or nil for the top level
+func (f *File) profile(name string) (*Settings, error) {
+	if name == "" {
+		return nil, nil
+	}
+	settings, ok := f.Profiles[name]
+	if !ok || settings == nil {
+		return nil, fmt.Errorf("no profile named %q in config file", name)
+	}
+	return settings, nil
+}
+
+// Lookup returns the effective value of key for profile ("" for none) and
+// where it came from: the environment variable, the profile, the top level
+// of the file, or "" if it is unset.
+func (f *File) Lookup(profile, key string) (value, source string, err error) {
+	k, err := lookupKey(key)
+	if err != nil {
+		return "", "", err
+	}
+	settings, err := f.profile(profile)
+	if err != nil {
+		return "", "", err
+	}
+
+	if value := os.Getenv(k.Env); value != "" {
+		return value, "env " + k.Env, nil
+	}
+	if settings != nil {
+		if value := k.get(settings); value != "" {
+			return value, "profile " + profile, nil
+		}
+	}
+	if value := k.get(&f.Settings); value != "" {
+		return value, "config", nil
+	}
+	return "", "", nil
+}
+
+// Resolve returns the effective settings for profile ("" for none): the
+// profile's values over the top-level values, with environment variables
+// taking precedence over both
+func (f *File) Resolve(profile string) (*Settings, error) {
+	var settings Settings
+	for _, k := range Keys {
+		value, source, err := f.Lookup(profile, k.Name)
+		if err != nil {
+			return nil, err
+		}
+		if value == "" {
+			continue
+		}
+		if err := k.set(&settings, value); err != nil {
+			return nil, fmt.Errorf("invalid %s from %s: %w", k.Name, source, err)
+		}
+	}
+	return &settings, nil
+}
+
+// Set validates and stores value for key in profile ("" for the top
+// level). An empty value removes the key.
+func (f *File) Set(profile, key, value string) error {
+	k, err := lookupKey(key)
+	if err != nil {
+		return err
+	}
+
+	settings := &f.Settings
+	if profile != "" {
+		if f.Profiles == nil {
+			f.Profiles = make(map[string]*Settings)
+		}
+		if f.Profiles[profile] == nil {
+			f.Profiles[profile] = &Settings{}
+		}
+		settings = f.Profiles[profile]
+	}
+	if err := k.set(settings, value); err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clearEnv unsets every key's environment variable for the test
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, k := range Keys {
+		t.Setenv(k.Env, "")
+	}
+}
+
+func testFile() *File {
+	work := &Settings{Output: "json", Limits: Limits{Search: 30}}
+	return &File{
+		Settings: Settings{
+			ClientID: "top-level-id",
+			Output:   "yaml",
+			Limits:   Limits{Search: 20, Ranking: 40},
+		},
+		Profiles: map[string]*Settings{"work": work},
+	}
+}
+
+func TestLookupPrecedence(t *testing.T) {
+	tests := []struct {
+		name       string
+		profile    string
+		key        string
+		env        string
+		wantValue  string
+		wantSource string
+	}{
+		{"top level", "", "output", "", "yaml", "config"},
+		{"profile over top level", "work", "output", "", "json", "profile work"},
+		{"profile falls back to top level", "work", "limits.ranking", "", "40", "config"},
+		{"environment over top level", "", "output", "csv", "csv", "env ZUTTO_OUTPUT"},
+		{"environment over profile", "work", "limits.search", "50", "50", "env ZUTTO_LIMITS_SEARCH"},
+		{"unset", "work", "title_language", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			k, err := lookupKey(tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv(k.Env, tt.env)
+
+			value, source, err := testFile().Lookup(tt.profile, tt.key)
+			if err != nil {
+				t.Fatalf("Lookup: %v", err)
+			}
+			if value != tt.wantValue || source != tt.wantSource {
+				t.Errorf("Lookup(%q, %q) = %q from %q, want %q from %q",
+					tt.profile, tt.key, value, source, tt.wantValue, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("ZUTTO_LIMITS_RANKING", "70")
+
+	settings, err := testFile().Resolve("work")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if settings.ClientID != "top-level-id" {
+		t.Errorf("client_id = %q, want the top-level value", settings.ClientID)
+	}
+	if settings.Output != "json" {
+		t.Errorf("output = %q, want the profile's json", settings.Output)
+	}
+	if settings.Limits.Search != 30 {
+		t.Errorf("limits.search = %d, want the profile's 30", settings.Limits.Search)
+	}
+	if settings.Limits.Ranking != 70 {
+		t.Errorf("limits.ranking = %d, want 70 from the environment", settings.Limits.Ranking)
+	}
+}
+
+func TestResolveRejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		env   string
+		value string
+	}{
+		{"ZUTTO_CACHE_DETAILS_TTL", "soon"},
+		{"ZUTTO_CACHE_SEARCH_TTL", "-1h"},
+		{"ZUTTO_LIMITS_SEARCH", "ten"},
+		{"ZUTTO_LIMITS_LIST", "0"},
+		{"ZUTTO_NSFW", "maybe"},
+		{"ZUTTO_OUTPUT", "xml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv(tt.env, tt.value)
+			_, err := testFile().Resolve("")
+			if err == nil {
+				t.Fatalf("Resolve accepted %s=%q", tt.env, tt.value)
+			}
+			if !strings.Contains(err.Error(), "env "+tt.env) {
+				t.Errorf("error %q does not name the environment variable", err)
+			}
+		})
+	}
+}
+
+func TestUnknownProfileAndKey(t *testing.T) {
+	clearEnv(t)
+	f := testFile()
+	if _, err := f.Resolve("home"); err == nil {
+		t.Error("Resolve accepted an unknown profile")
+	}
+	if _, _, err := f.Lookup("home", "output"); err == nil {
+		t.Error("Lookup accepted an unknown profile")
+	}
+	if _, _, err := f.Lookup("", "colour"); err == nil {
+		t.Error("Lookup accepted an unknown key")
+	}
+	if err := f.Set("", "colour", "red"); err == nil {
+		t.Error("Set accepted an unknown key")
+	}
+}
+
+func TestSet(t *testing.T) {
+	clearEnv(t)
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"duration", "cache.details_ttl", "12h", "12h0m0s", false},
+		{"invalid duration", "cache.details_ttl", "12 hours", "", true},
+		{"int", "limits.season", "200", "200", false},
+		{"invalid int", "limits.season", "many", "", true},
+		{"negative int", "limits.season", "-5", "", true},
+		{"bool", "nsfw", "true", "true", false},
+		{"invalid title language", "title_language", "klingon", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &File{}
+			err := f.Set("", tt.key, tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Set(%q, %q) succeeded, want an error", tt.key, tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Set(%q, %q): %v", tt.key, tt.value, err)
+			}
+			if value, _, _ := f.Lookup("", tt.key); value != tt.want {
+				t.Errorf("after Set(%q, %q), Lookup = %q, want %q", tt.key, tt.value, value, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetEmptyRemovesKey(t *testing.T) {
+	clearEnv(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	f := testFile()
+	if err := f.Set("work", "cache.details_ttl", "6h"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Set("work", "output", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Set("", "cache.details_ttl", "1h"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Set("", "cache.details_ttl", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(string(data), "output:") != 1 {
+		t.Errorf("the profile's output is still in the file:\n%s", data)
+	}
+	if strings.Count(string(data), "details_ttl:") != 1 {
+		t.Errorf("the top-level details_ttl is still in the file:\n%s", data)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	settings, err := loaded.Resolve("work")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if settings.Output != "yaml" {
+		t.Errorf("output = %q, want the top-level yaml once the profile's is removed", settings.Output)
+	}
+	if settings.Cache.DetailsTTL != 6*time.Hour {
+		t.Errorf("details_ttl = %v, want the profile's 6h", settings.Cache.DetailsTTL)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	f, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if f.ClientID != "" || len(f.Profiles) != 0 {
+		t.Errorf("missing file loaded as %+v, want empty", f)
+	}
+}
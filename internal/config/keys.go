@@ -0,0 +1,166 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+	"github.com/bradleyyma/zutto/internal/render"
+)
+
+// Key is a setting that can be read with `zutto config get` and written
+// with `zutto config set`
+type Key struct {
+	Name        string
+	Env         string
+	Description string
+
+	get func(s *Settings) string
+	// set parses and stores a value; "" clears the setting
+	set func(s *Settings, value string) error
+}
+
+// Keys lists every setting in the order `zutto config list` shows them
+var Keys = []Key{
+	{
+		Name:        "client_id",
+		Env:         "MAL_CLIENT_ID",
+		Description: "MyAnimeList API client ID",
+		get:         func(s *Settings) string { return s.ClientID },
+		set:         func(s *Settings, v string) error { s.ClientID = v; return nil },
+	},
+	{
+		Name:        "output",
+		Env:         "ZUTTO_OUTPUT",
+		Description: "Default output format (text, json, jsonl, yaml, csv, tsv)",
+		get:         func(s *Settings) string { return s.Output },
+		set: func(s *Settings, v string) error {
+			if v != "" {
+				if err := render.ValidateFormat(v); err != nil {
+					return err
+				}
+			}
+			s.Output = v
+			return nil
+		},
+	},
+	{
+		Name:        "title_language",
+		Env:         "ZUTTO_TITLE_LANGUAGE",
+		Description: "Language titles are shown in (romaji, english, native)",
+		get:         func(s *Settings) string { return s.TitleLanguage },
+		set: func(s *Settings, v string) error {
+			if v != "" {
+				if err := mal.ValidateTitleLanguage(v); err != nil {
+					return err
+				}
+			}
+			s.TitleLanguage = v
+			return nil
+		},
+	},
+	{
+		Name:        "nsfw",
+		Env:         "ZUTTO_NSFW",
+		Description: "Include NSFW entries in search, ranking and seasonal results (true, false)",
+		get: func(s *Settings) string {
+			if s.NSFW == nil {
+				return ""
+			}
+			return strconv.FormatBool(*s.NSFW)
+		},
+		set: func(s *Settings, v string) error {
+			if v == "" {
+				s.NSFW = nil
+				return nil
+			}
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("must be true or false, got %q", v)
+			}
+			s.NSFW = &b
+			return nil
+		},
+	},
+	intKey("limits.search", "Default --limit for anime and manga search", func(s *Settings) *int { return &s.Limits.Search }),
+	intKey("limits.ranking", "Default --limit for anime and manga rankings", func(s *Settings) *int { return &s.Limits.Ranking }),
+	intKey("limits.season", "Default --limit for anime season", func(s *Settings) *int { return &s.Limits.Season }),
+	intKey("limits.list", "Default --limit for list show", func(s *Settings) *int { return &s.Limits.List }),
+	durationKey("cache.details_ttl", "How long anime and manga details are cached", func(s *Settings) *time.Duration { return &s.Cache.DetailsTTL }),
+	durationKey("cache.search_ttl", "How long search results are cached", func(s *Settings) *time.Duration { return &s.Cache.SearchTTL }),
+	durationKey("cache.rankings_ttl", "How long rankings are cached", func(s *Settings) *time.Duration { return &s.Cache.RankingsTTL }),
+	durationKey("cache.seasonal_ttl", "How long seasonal charts are cached", func(s *Settings) *time.Duration { return &s.Cache.SeasonalTTL }),
+}
+
+func lookupKey(name string) (*Key, error) {
+	for i := range Keys {
+		if Keys[i].Name == name {
+			return &Keys[i], nil
+		}
+	}
+	names := make([]string, len(Keys))
+	for i, k := range Keys {
+		names[i] = k.Name
+	}
+	return nil, fmt.Errorf("unknown config key %q, available keys: %s", name, strings.Join(names, ", "))
+}
+
+// envName derives the environment variable for a key, e.g. limits.search
+// becomes ZUTTO_LIMITS_SEARCH
+func envName(name string) string {
+	return "ZUTTO_" + strings.ToUpper(strings.ReplaceAll(name, ".", "_"))
+}
+
+func intKey(name, description string, field func(s *Settings) *int) Key {
+	return Key{
+		Name:        name,
+		Env:         envName(name),
+		Description: description,
+		get: func(s *Settings) string {
+			if n := *field(s); n != 0 {
+				return strconv.Itoa(n)
+			}
+			return ""
+		},
+		set: func(s *Settings, v string) error {
+			if v == "" {
+				*field(s) = 0
+				return nil
+			}
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("must be a positive integer, got %q", v)
+			}
+			*field(s) = n
+			return nil
+		},
+	}
+}
+
+func durationKey(name, description string, field func(s *Settings) *time.Duration) Key {
+	return Key{
+		Name:        name,
+		Env:         envName(name),
+		Description: description,
+		get: func(s *Settings) string {
+			if d := *field(s); d != 0 {
+				return d.String()
+			}
+			return ""
+		},
+		set: func(s *Settings, v string) error {
+			if v == "" {
+				*field(s) = 0
+				return nil
+			}
+			d, err := time.ParseDuration(v)
+			if err != nil || d < 0 {
+				return fmt.Errorf("must be a duration such as 30m or 12h, got %q", v)
+			}
+			*field(s) = d
+			return nil
+		},
+	}
+}
@@ -1,12 +1,11 @@
 package mal
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
-	"sync"
 )
 
 type AnimeService struct {
@@ -21,10 +20,10 @@ type AnimeSearchResponse struct {
 
 // AnimeData represents individual anime items in the response
 type AnimeData struct {
-	Node AnimeNode `json:"node"`
+	Node AnimeDetails `json:"node"`
 }
 
-// AnimeNode contains the actual anime information
+// AnimeNode is the short form MAL uses when linking to an anime
 type AnimeNode struct {
 	ID                int               `json:"id"`
 	Title             string            `json:"title"`
@@ -32,16 +31,96 @@ type AnimeNode struct {
 	AlternativeTitles AlternativeTitles `json:"alternative_titles,omitempty"`
 }
 
+// AnimeDetails is the full MAL anime model. Which fields are populated
+// depends on the fields requested.
 type AnimeDetails struct {
-	Title       string  `json:"title"`
-	StartDate   string  `json:"start_date,omitempty"`
-	EndDate     string  `json:"end_date,omitempty"`
-	Mean        float64 `json:"mean,omitempty"`
-	Rank        int     `json:"rank,omitempty"`
-	Popularity  int     `json:"popularity,omitempty"`
-	Status      string  `json:"status,omitempty"`
-	NumEpisodes int     `json:"num_episodes,omitempty"`
-	Synopsis    string  `json:"synopsis,omitempty"`
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	// DisplayTitle is not part of the MAL API. SetDisplayTitle fills it
+	// with the title in a preferred language.
+	DisplayTitle           string                `json:"display_title,omitempty"`
+	MainPicture            Picture               `json:"main_picture,omitempty"`
+	AlternativeTitles      AlternativeTitles     `json:"alternative_titles,omitempty"`
+	StartDate              string                `json:"start_date,omitempty"`
+	EndDate                string                `json:"end_date,omitempty"`
+	Mean                   float64               `json:"mean,omitempty"`
+	Rank                   int                   `json:"rank,omitempty"`
+	Popularity             int                   `json:"popularity,omitempty"`
+	NumListUsers           int                   `json:"num_list_users,omitempty"`
+	NumScoringUsers        int                   `json:"num_scoring_users,omitempty"`
+	NSFW                   string                `json:"nsfw,omitempty"`
+	Genres                 []Genre               `json:"genres,omitempty"`
+	CreatedAt              string                `json:"created_at,omitempty"`
+	UpdatedAt              string                `json:"updated_at,omitempty"`
+	MediaType              string                `json:"media_type,omitempty"`
+	Status                 string                `json:"status,omitempty"`
+	MyListStatus           *AnimeListStatus      `json:"my_list_status,omitempty"`
+	NumEpisodes            int                   `json:"num_episodes,omitempty"`
+	StartSeason            *Season               `json:"start_season,omitempty"`
+	Broadcast              *Broadcast            `json:"broadcast,omitempty"`
+	Source                 string                `json:"source,omitempty"`
+	AverageEpisodeDuration int                   `json:"average_episode_duration,omitempty"`
+	Rating                 string                `json:"rating,omitempty"`
+	Studios                []Studio              `json:"studios,omitempty"`
+	Pictures               []Picture             `json:"pictures,omitempty"`
+	Synopsis               string                `json:"synopsis,omitempty"`
+	Background             string                `json:"background,omitempty"`
+	RelatedAnime           []RelatedAnime        `json:"related_anime,omitempty"`
+	RelatedManga           []RelatedManga        `json:"related_manga,omitempty"`
+	Recommendations        []AnimeRecommendation `json:"recommendations,omitempty"`
+	Statistics             *AnimeStatistics      `json:"statistics,omitempty"`
+}
+
+// Season identifies an anime season, e.g. fall 2025
+type Season struct {
+	Year   int    `json:"year"`
+	Season string `json:"season"`
+}
+
+// Broadcast is the weekly airing slot in Japan Standard Time
+type Broadcast struct {
+	DayOfTheWeek string `json:"day_of_the_week"`
+	StartTime    string `json:"start_time,omitempty"`
+}
+
+type Studio struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// RelatedAnime links to another anime, e.g. a sequel or side story
+type RelatedAnime struct {
+	Node                  AnimeNode `json:"node"`
+	RelationType          string    `json:"relation_type"`
+	RelationTypeFormatted string    `json:"relation_type_formatted"`
+}
+
+// RelatedManga links to a manga, e.g. the source material
+type RelatedManga struct {
+	Node                  MangaNode `json:"node"`
+	RelationType          string    `json:"relation_type"`
+	RelationTypeFormatted string    `json:"relation_type_formatted"`
+}
+
+// AnimeRecommendation is a user recommendation from an anime's page
+type AnimeRecommendation struct {
+	Node               AnimeNode `json:"node"`
+	NumRecommendations int       `json:"num_recommendations"`
+}
+
+// AnimeStatistics counts list entries by status
+type AnimeStatistics struct {
+	Status       AnimeStatusCounts `json:"status"`
+	NumListUsers int               `json:"num_list_users"`
+}
+
+// AnimeStatusCounts are reported by MAL as strings
+type AnimeStatusCounts struct {
+	Watching    string `json:"watching"`
+	Completed   string `json:"completed"`
+	OnHold      string `json:"on_hold"`
+	Dropped     string `json:"dropped"`
+	PlanToWatch string `json:"plan_to_watch"`
 }
 
 // Picture contains image URLs
@@ -67,8 +146,8 @@ type Ranking struct {
 }
 
 type AnimeRankingData struct {
-	Node    AnimeNode `json:"node"`
-	Ranking Ranking   `json:"ranking"`
+	Node    AnimeDetails `json:"node"`
+	Ranking Ranking      `json:"ranking"`
 }
 
 type AnimeRankingResponse struct {
@@ -76,15 +155,26 @@ type AnimeRankingResponse struct {
 	Paging Paging             `json:"paging"`
 }
 
-func (a *AnimeService) Search(query string, limit int) (*AnimeSearchResponse, error) {
+// Search finds anime by title. fields selects the data returned for each
+// result (see AnimeFields); by default only titles are included.
+func (a *AnimeService) Search(query string, limit int, fields ...string) (*AnimeSearchResponse, error) {
+	return a.SearchContext(context.Background(), query, limit, fields...)
+}
+
+// SearchContext is like Search but uses ctx for the request
+func (a *AnimeService) SearchContext(ctx context.Context, query string, limit int, fields ...string) (*AnimeSearchResponse, error) {
 	// URL encode the query parameter
 	params := url.Values{}
 	params.Add("q", query)
 	params.Add("limit", fmt.Sprintf("%d", limit)) // Optional: limit results
+	params.Add("fields", fieldsParam(fields, DefaultAnimeListFields))
+	if a.client.nsfw {
+		params.Add("nsfw", "true")
+	}
 
 	reqURL := a.client.baseURL.String() + "anime?" + params.Encode()
 
-	req, err := http.NewRequest("GET", reqURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -97,8 +187,7 @@ func (a *AnimeService) Search(query string, limit int) (*AnimeSearchResponse, er
 
 	// Check for successful status code
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError(resp)
 	}
 
 	// Read and parse the JSON response
@@ -106,15 +195,23 @@ func (a *AnimeService) Search(query string, limit int) (*AnimeSearchResponse, er
 	if err := json.NewDecoder(resp.Body).Decode(&searchResponse); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
+	a.client.recordAnime(ctx, &searchResponse)
 
 	return &searchResponse, nil
 }
 
-func (a *AnimeService) Details(animeID int) (*AnimeDetails, error) {
-	field := "id,title,synopsis,num_episodes,status,start_date,end_date,mean,rank,popularity"
-	reqURL := a.client.baseURL.String() + "anime/" + fmt.Sprintf("%d", animeID) + "?fields=" + field
+// Details fetches one anime. fields selects the data returned (see
+// AnimeFields); DefaultAnimeDetailsFields is used when none are given.
+func (a *AnimeService) Details(animeID int, fields ...string) (*AnimeDetails, error) {
+	return a.DetailsContext(context.Background(), animeID, fields...)
+}
 
-	req, err := http.NewRequest("GET", reqURL, nil)
+// DetailsContext is like Details but uses ctx for the request
+func (a *AnimeService) DetailsContext(ctx context.Context, animeID int, fields ...string) (*AnimeDetails, error) {
+	field := fieldsParam(fields, DefaultAnimeDetailsFields)
+	reqURL := a.client.baseURL.String() + "anime/" + fmt.Sprintf("%d", animeID) + "?fields=" + url.QueryEscape(field)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -126,47 +223,46 @@ func (a *AnimeService) Details(animeID int) (*AnimeDetails, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError(resp)
 	}
 
 	var details AnimeDetails
 	if err := json.NewDecoder(resp.Body).Decode(&details); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
+	a.client.recordAnime(ctx, &details)
 
 	return &details, nil
 }
 
-func (a *AnimeService) BatchDetails(animeIDs []int) ([]AnimeDetails, error) {
-	detailsList := make([]AnimeDetails, len(animeIDs))
-	var wg sync.WaitGroup
-	errChan := make(chan error, len(animeIDs))
-	for i, id := range animeIDs {
-		wg.Add(1)
-		go func(i, id int) {
-			defer wg.Done()
-			details, err := a.Details(id)
-			if err != nil {
-				errChan <- fmt.Errorf("failed to fetch details for anime ID %d: %w", id, err)
-				return
-			}
-			detailsList[i] = *details
-		}(i, id)
-	}
-	wg.Wait()
-	close(errChan)
+// BatchDetails fetches details for several anime at once. Repeated IDs are
+// fetched once. IDs that fail are reported in the result's Errors rather than
+// failing the whole batch.
+func (a *AnimeService) BatchDetails(animeIDs []int, fields ...string) (*BatchResult[AnimeDetails], error) {
+	return a.BatchDetailsContext(context.Background(), animeIDs, fields...)
+}
 
-	if len(errChan) > 0 {
-		return nil, <-errChan
-	}
+// BatchDetailsContext is like BatchDetails but uses ctx for its requests
+func (a *AnimeService) BatchDetailsContext(ctx context.Context, animeIDs []int, fields ...string) (*BatchResult[AnimeDetails], error) {
+	return batchFetch(ctx, animeIDs, a.client.batchConcurrency, func(ctx context.Context, id int) (*AnimeDetails, error) {
+		return a.DetailsContext(ctx, id, fields...)
+	})
+}
 
-	return detailsList, nil
+// Rankings returns the top anime for a ranking type. fields selects the
+// data returned for each entry (see AnimeFields).
+func (a *AnimeService) Rankings(rankingType string, limit, offset int, fields ...string) (*AnimeRankingResponse, error) {
+	return a.RankingsContext(context.Background(), rankingType, limit, offset, fields...)
 }
 
-func (a *AnimeService) Rankings(rankingType string, limit, offset int) (*AnimeRankingResponse, error) {
-	reqURL := a.client.baseURL.String() + "anime/ranking?ranking_type=" + rankingType + fmt.Sprintf("&limit=%d&offset=%d", limit, offset)
-	req, err := http.NewRequest("GET", reqURL, nil)
+// RankingsContext is like Rankings but uses ctx for the request
+func (a *AnimeService) RankingsContext(ctx context.Context, rankingType string, limit, offset int, fields ...string) (*AnimeRankingResponse, error) {
+	reqURL := a.client.baseURL.String() + "anime/ranking?ranking_type=" + rankingType + fmt.Sprintf("&limit=%d&offset=%d", limit, offset) +
+		"&fields=" + url.QueryEscape(fieldsParam(fields, DefaultAnimeListFields))
+	if a.client.nsfw {
+		reqURL += "&nsfw=true"
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -177,20 +273,22 @@ func (a *AnimeService) Rankings(rankingType string, limit, offset int) (*AnimeRa
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError(resp)
 	}
 
 	var rankings AnimeRankingResponse
 	if err := json.NewDecoder(resp.Body).Decode(&rankings); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
+	a.client.recordAnime(ctx, &rankings)
 	return &rankings, nil
 }
 
 func ValidateAnimeRankingType(rankingType string) error {
 	validTypes := map[string]bool{
 		"all":          true,
+		"airing":       true,
+		"upcoming":     true,
 		"tv":           true,
 		"movie":        true,
 		"ova":          true,
@@ -0,0 +1,301 @@
+package mal
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	malAuthURL  = "https://myanimelist.net/v1/oauth2/authorize"
+	malTokenURL = "https://myanimelist.net/v1/oauth2/token"
+
+	// DefaultRedirectURL is the loopback address the login flow listens on.
+	// It must match the App Redirect URL registered for the MAL client ID.
+	DefaultRedirectURL = "http://localhost:8765/callback"
+)
+
+// ErrNotLoggedIn is returned when no stored token is available
+var ErrNotLoggedIn = errors.New("not logged in: run `zutto auth login`")
+
+// TokenStore persists OAuth2 tokens between runs
+type TokenStore interface {
+	Load() (*oauth2.Token, error)
+	Save(token *oauth2.Token) error
+	Delete() error
+}
+
+// FileTokenStore stores a token as JSON in a file readable only by the current user
+type FileTokenStore struct {
+	Path string
+}
+
+// DefaultTokenStore returns a FileTokenStore under the user's config directory
+func DefaultTokenStore() (*FileTokenStore, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return &FileTokenStore{Path: filepath.Join(dir, "zutto", "token.json")}, nil
+}
+
+// Load reads the stored token, returning ErrNotLoggedIn if there is none
+func (s *FileTokenStore) Load() (*oauth2.Token, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotLoggedIn
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token file: %w", err)
+	}
+
+	var token oauth2.Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("failed to decode token file: %w", err)
+	}
+	return &token, nil
+}
+
+// Save writes the token with 0600 permissions, replacing any existing file
+func (s *FileTokenStore) Save(token *oauth2.Token) error {
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o700); err != nil {
+		return fmt.Errorf("failed to create token directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode token: %w", err)
+	}
+
+	// Write to a temp file first so a crash never leaves a truncated token behind
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), ".token-*")
+	if err != nil {
+		return fmt.Errorf("failed to create token file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set token file permissions: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.Path); err != nil {
+		return fmt.Errorf("failed to replace token file: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the stored token. Deleting a missing token is not an error.
+func (s *FileTokenStore) Delete() error {
+	if err := os.Remove(s.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove token file: %w", err)
+	}
+	return nil
+}
+
+// Authenticator runs MAL's OAuth2 PKCE flow and hands out refreshed tokens
+type Authenticator struct {
+	Config *oauth2.Config
+	Store  TokenStore
+
+	// HTTPClient is used for token exchange and refresh requests
+	HTTPClient *http.Client
+}
+
+// NewAuthenticator creates an Authenticator for the MAL OAuth2 endpoints.
+// If clientID is empty, MAL_CLIENT_ID is used; if clientSecret is empty,
+// MAL_CLIENT_SECRET is used (it may legitimately be empty for "other" apps).
+func NewAuthenticator(clientID, clientSecret string, store TokenStore) *Authenticator {
+	if clientID == "" {
+		clientID = os.Getenv("MAL_CLIENT_ID")
+	}
+	if clientSecret == "" {
+		clientSecret = os.Getenv("MAL_CLIENT_SECRET")
+	}
+
+	return &Authenticator{
+		Config: &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  DefaultRedirectURL,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:   malAuthURL,
+				TokenURL:  malTokenURL,
+				AuthStyle: oauth2.AuthStyleInParams,
+			},
+		},
+		Store: store,
+	}
+}
+
+func (a *Authenticator) context(ctx context.Context) context.Context {
+	if a.HTTPClient == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, oauth2.HTTPClient, a.HTTPClient)
+}
+
+// Login runs the authorization-code flow. It listens on the loopback redirect
+// URL, passes the authorization URL to openURL, waits for the redirect and
+// exchanges the code for a token, which is saved to the store.
+//
+// MAL only supports the "plain" PKCE challenge method, so the verifier is
+// sent as the challenge verbatim.
+func (a *Authenticator) Login(ctx context.Context, openURL func(authURL string) error) (*oauth2.Token, error) {
+	redirect, err := url.Parse(a.Config.RedirectURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redirect URL: %w", err)
+	}
+	callbackPath := redirect.Path
+	if callbackPath == "" {
+		callbackPath = "/"
+	}
+
+	listener, err := net.Listen("tcp", redirect.Host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", redirect.Host, err)
+	}
+
+	state, err := randomState()
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	type callbackResult struct {
+		code string
+		err  error
+	}
+	results := make(chan callbackResult, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(callbackPath, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		var result callbackResult
+		switch {
+		case query.Get("error") != "":
+			result.err = fmt.Errorf("authorization denied: %s %s", query.Get("error"), query.Get("error_description"))
+		case query.Get("state") != state:
+			result.err = fmt.Errorf("authorization state mismatch")
+		case query.Get("code") == "":
+			result.err = fmt.Errorf("authorization response did not include a code")
+		default:
+			result.code = query.Get("code")
+		}
+
+		if result.err != nil {
+			http.Error(w, result.err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "zutto is now authorized. You can close this window.")
+		}
+
+		select {
+		case results <- result:
+		default:
+		}
+	})
+
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Close()
+
+	authURL := a.Config.AuthCodeURL(state,
+		oauth2.SetAuthURLParam("code_challenge", verifier),
+		oauth2.SetAuthURLParam("code_challenge_method", "plain"),
+	)
+	if err := openURL(authURL); err != nil {
+		return nil, err
+	}
+
+	var result callbackResult
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result = <-results:
+	}
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	token, err := a.Config.Exchange(a.context(ctx), result.code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+
+	if err := a.Store.Save(token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// TokenSource returns a token source backed by the store. Expired tokens are
+// refreshed automatically and the refreshed token is written back.
+func (a *Authenticator) TokenSource(ctx context.Context) oauth2.TokenSource {
+	return &storeTokenSource{ctx: a.context(ctx), auth: a}
+}
+
+type storeTokenSource struct {
+	ctx  context.Context
+	auth *Authenticator
+
+	mu     sync.Mutex
+	source oauth2.TokenSource
+	last   string
+	failed error // a failed refresh, not retried on every request
+}
+
+func (s *storeTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failed != nil {
+		return nil, s.failed
+	}
+	if s.source == nil {
+		token, err := s.auth.Store.Load()
+		if err != nil {
+			return nil, err
+		}
+		s.source = s.auth.Config.TokenSource(s.ctx, token)
+		s.last = token.AccessToken
+	}
+
+	token, err := s.source.Token()
+	if err != nil {
+		s.failed = fmt.Errorf("failed to refresh token: %w", err)
+		return nil, s.failed
+	}
+	if token.AccessToken != s.last {
+		if err := s.auth.Store.Save(token); err != nil {
+			return nil, err
+		}
+		s.last = token.AccessToken
+	}
+	return token, nil
+}
+
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate state: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
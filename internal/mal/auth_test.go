@@ -0,0 +1,273 @@
+package mal
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// fakeAuthServer stands in for MAL's token endpoint
+type fakeAuthServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	challenge string // code_challenge from the last authorization URL
+	refreshes int
+	revoked   bool
+}
+
+func newFakeAuthServer(t *testing.T) *fakeAuthServer {
+	t.Helper()
+	s := &fakeAuthServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.token))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fakeAuthServer) token(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var access string
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		if r.PostForm.Get("code") != "the-code" || r.PostForm.Get("code_verifier") != s.challenge {
+			tokenError(w, "invalid_grant")
+			return
+		}
+		access = "access-1"
+	case "refresh_token":
+		if s.revoked || r.PostForm.Get("refresh_token") != "refresh" {
+			tokenError(w, "invalid_grant")
+			return
+		}
+		s.refreshes++
+		access = "access-refreshed"
+	default:
+		tokenError(w, "unsupported_grant_type")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"token_type":    "Bearer",
+		"access_token":  access,
+		"refresh_token": "refresh",
+		"expires_in":    3600,
+	})
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
+
+// newTestAuthenticator points an Authenticator at server, with its login
+// redirect on a free loopback port and tokens stored under a temp directory
+func newTestAuthenticator(t *testing.T, server *fakeAuthServer) *Authenticator {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	store := &FileTokenStore{Path: filepath.Join(t.TempDir(), "zutto", "token.json")}
+	auth := NewAuthenticator("client-id", "", store)
+	auth.Config.RedirectURL = "http://" + addr + "/callback"
+	auth.Config.Endpoint.AuthURL = server.URL + "/authorize"
+	auth.Config.Endpoint.TokenURL = server.URL + "/token"
+	auth.HTTPClient = server.Client()
+	return auth
+}
+
+func TestLoginExchangesCodeAndSavesToken(t *testing.T) {
+	server := newFakeAuthServer(t)
+	auth := newTestAuthenticator(t, server)
+
+	// Play the browser: approve the request and follow the redirect
+	openURL := func(authURL string) error {
+		u, err := url.Parse(authURL)
+		if err != nil {
+			return err
+		}
+		query := u.Query()
+		if query.Get("code_challenge_method") != "plain" || query.Get("client_id") != "client-id" {
+			t.Errorf("unexpected authorization URL %s", authURL)
+		}
+		server.mu.Lock()
+		server.challenge = query.Get("code_challenge")
+		server.mu.Unlock()
+
+		callback := query.Get("redirect_uri") + "?" + url.Values{
+			"code":  {"the-code"},
+			"state": {query.Get("state")},
+		}.Encode()
+		go func() {
+			resp, err := http.Get(callback)
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	token, err := auth.Login(ctx, openURL)
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if token.AccessToken != "access-1" {
+		t.Errorf("access token = %q, want access-1", token.AccessToken)
+	}
+
+	path := auth.Store.(*FileTokenStore).Path
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("token file mode = %o, want 600", mode)
+	}
+	saved, err := auth.Store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.AccessToken != "access-1" || saved.RefreshToken != "refresh" {
+		t.Errorf("saved token = %+v", saved)
+	}
+}
+
+func TestLoginRejectsStateMismatch(t *testing.T) {
+	server := newFakeAuthServer(t)
+	auth := newTestAuthenticator(t, server)
+
+	openURL := func(authURL string) error {
+		u, _ := url.Parse(authURL)
+		callback := u.Query().Get("redirect_uri") + "?code=the-code&state=forged"
+		go func() {
+			resp, err := http.Get(callback)
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := auth.Login(ctx, openURL); err == nil {
+		t.Fatal("Login accepted a callback with the wrong state")
+	}
+	if _, err := auth.Store.Load(); err != ErrNotLoggedIn {
+		t.Errorf("Load after failed login = %v, want ErrNotLoggedIn", err)
+	}
+}
+
+func TestTokenSourceRefreshesExpiredToken(t *testing.T) {
+	server := newFakeAuthServer(t)
+	auth := newTestAuthenticator(t, server)
+	expired := &oauth2.Token{
+		AccessToken:  "access-old",
+		RefreshToken: "refresh",
+		TokenType:    "Bearer",
+		Expiry:       time.Now().Add(-time.Hour),
+	}
+	if err := auth.Store.Save(expired); err != nil {
+		t.Fatal(err)
+	}
+
+	ts := auth.TokenSource(context.Background())
+	for range 2 {
+		token, err := ts.Token()
+		if err != nil {
+			t.Fatalf("Token: %v", err)
+		}
+		if token.AccessToken != "access-refreshed" {
+			t.Errorf("access token = %q, want access-refreshed", token.AccessToken)
+		}
+	}
+	if server.refreshes != 1 {
+		t.Errorf("refreshed %d times, want 1", server.refreshes)
+	}
+
+	saved, err := auth.Store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.AccessToken != "access-refreshed" {
+		t.Errorf("saved access token = %q, want access-refreshed", saved.AccessToken)
+	}
+	info, err := os.Stat(auth.Store.(*FileTokenStore).Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("token file mode after refresh = %o, want 600", mode)
+	}
+}
+
+func TestClientWithFailingTokenSource(t *testing.T) {
+	server := newFakeAuthServer(t)
+	server.revoked = true
+	auth := newTestAuthenticator(t, server)
+	if err := auth.Store.Save(&oauth2.Token{
+		AccessToken:  "access-old",
+		RefreshToken: "refresh",
+		Expiry:       time.Now().Add(-time.Hour),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-MAL-CLIENT-ID") != "client-id" {
+			t.Errorf("missing client ID header on %s", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("sent Authorization %q with an unusable token", auth)
+		}
+		if r.URL.Path == "/users/@me" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_token"}`))
+			return
+		}
+		w.Write([]byte(`{"data":[],"paging":{}}`))
+	}))
+	defer api.Close()
+
+	client := NewClient(nil, "client-id")
+	client.SetBaseURL(api.URL)
+	client.SetRetryPolicy(RetryPolicy{})
+	client.SetTokenSource(auth.TokenSource(context.Background()))
+
+	// Public endpoints work without the token
+	if _, err := client.Anime.SearchContext(context.Background(), "frieren", 1); err != nil {
+		t.Fatalf("search with a revoked token: %v", err)
+	}
+
+	// Endpoints that need login report why there is no token
+	_, err := client.User.MeContext(context.Background())
+	if err == nil || !IsUnauthorized(err) {
+		t.Fatalf("MeContext error = %v, want a 401", err)
+	}
+	if want := "failed to refresh token"; !strings.Contains(err.Error(), want) {
+		t.Errorf("MeContext error %q does not mention %q", err, want)
+	}
+}
@@ -0,0 +1,101 @@
+package mal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// DefaultBatchConcurrency is the number of concurrent requests BatchDetails
+// makes unless changed with SetBatchConcurrency
+const DefaultBatchConcurrency = 4
+
+// BatchResult holds the outcome of a batch fetch. Results and Errors are each
+// ordered by the first position of their ID in the request.
+type BatchResult[T any] struct {
+	Results []T          `json:"results"`
+	Errors  []BatchError `json:"errors,omitempty"`
+}
+
+// BatchError records why one ID in a batch could not be fetched
+type BatchError struct {
+	ID  int
+	Err error
+}
+
+func (e BatchError) Error() string {
+	return fmt.Sprintf("ID %d: %v", e.ID, e.Err)
+}
+
+func (e BatchError) Unwrap() error {
+	return e.Err
+}
+
+func (e BatchError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ID    int    `json:"id"`
+		Error string `json:"error"`
+	}{e.ID, e.Err.Error()})
+}
+
+// SetBatchConcurrency sets how many requests BatchDetails runs at once
+func (c *Client) SetBatchConcurrency(n int) {
+	if n < 1 {
+		n = 1
+	}
+	c.batchConcurrency = n
+}
+
+// batchFetch fetches each distinct ID with at most concurrency requests in
+// flight. It returns ctx.Err() if the context ends before every ID is done.
+func batchFetch[T any](ctx context.Context, ids []int, concurrency int, fetch func(context.Context, int) (*T, error)) (*BatchResult[T], error) {
+	seen := make(map[int]bool, len(ids))
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	results := make([]*T, len(unique))
+	errs := make([]error, len(unique))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(concurrency, len(unique)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = fetch(ctx, unique[i])
+			}
+		}()
+	}
+
+feed:
+	for i := range unique {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	batch := &BatchResult[T]{Results: make([]T, 0, len(unique))}
+	for i, id := range unique {
+		if errs[i] != nil {
+			batch.Errors = append(batch.Errors, BatchError{ID: id, Err: errs[i]})
+			continue
+		}
+		batch.Results = append(batch.Results, *results[i])
+	}
+	return batch, nil
+}
@@ -0,0 +1,264 @@
+package mal
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Cache stores raw API responses keyed by request URL
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration) error
+}
+
+// CachePolicy decides how long each kind of response is cached. A zero TTL
+// disables caching for that kind. Responses that depend on the logged-in
+// user (lists, my_list_status, suggestions) are never cached.
+type CachePolicy struct {
+	DetailsTTL  time.Duration
+	SearchTTL   time.Duration
+	RankingsTTL time.Duration
+	SeasonalTTL time.Duration
+
+	// Refresh skips cache reads but still stores fresh responses
+	Refresh bool
+}
+
+// DefaultCachePolicy keeps details for a day, seasonal charts for a few
+// hours and search results and rankings, which change more often, for an hour
+var DefaultCachePolicy = CachePolicy{
+	DetailsTTL:  24 * time.Hour,
+	SearchTTL:   time.Hour,
+	RankingsTTL: time.Hour,
+	SeasonalTTL: 6 * time.Hour,
+}
+
+var detailsPath = regexp.MustCompile(`^(anime|manga)/\d+$`)
+
+// ttl returns how long a GET to path (relative to the API root) with the
+// given query may be cached
+func (p CachePolicy) ttl(path string, query string) time.Duration {
+	if strings.Contains(query, "my_list_status") {
+		return 0
+	}
+	switch {
+	case strings.HasPrefix(path, "users/"):
+		return 0
+	case path == "anime/ranking" || path == "manga/ranking":
+		return p.RankingsTTL
+	case strings.HasPrefix(path, "anime/season/"):
+		return p.SeasonalTTL
+	case path == "anime" || path == "manga":
+		return p.SearchTTL
+	case detailsPath.MatchString(path):
+		return p.DetailsTTL
+	}
+	return 0
+}
+
+// SetCache enables response caching for GET requests. Pass a nil cache to
+// disable it.
+func (c *Client) SetCache(cache Cache, policy CachePolicy) {
+	c.cache = cache
+	c.cachePolicy = policy
+}
+
+// cacheTTL returns the TTL for req, or 0 if it must not be cached
+func (c *Client) cacheTTL(req *http.Request) time.Duration {
+	if c.cache == nil || req.Method != http.MethodGet {
+		return 0
+	}
+	path := strings.TrimPrefix(req.URL.Path, c.baseURL.Path)
+	return c.cachePolicy.ttl(path, req.URL.RawQuery)
+}
+
+// cachedResponse builds a response for a cache hit
+func cachedResponse(req *http.Request, body []byte) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// storeResponse reads a successful response into the cache and replaces its
+// body so the caller can still decode it
+func (c *Client) storeResponse(resp *http.Response, key string, ttl time.Duration) error {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	// A cache write failure only costs a future request
+	c.cache.Set(key, body, ttl)
+	return nil
+}
+
+// DiskCache is a Cache that keeps one file per entry in a directory
+type DiskCache struct {
+	Dir string
+}
+
+// DefaultDiskCache returns a DiskCache under the user's cache directory
+func DefaultDiskCache() (*DiskCache, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate cache directory: %w", err)
+	}
+	return &DiskCache{Dir: filepath.Join(dir, "zutto", "responses")}, nil
+}
+
+type diskEntry struct {
+	Key     string          `json:"key"`
+	Expires time.Time       `json:"expires"`
+	Body    json.RawMessage `json:"body"`
+}
+
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (d *DiskCache) Get(key string) ([]byte, bool) {
+	data, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var entry diskEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Key != key {
+		return nil, false
+	}
+	if time.Now().After(entry.Expires) {
+		os.Remove(d.path(key))
+		return nil, false
+	}
+	return entry.Body, true
+}
+
+func (d *DiskCache) Set(key string, value []byte, ttl time.Duration) error {
+	if !json.Valid(value) {
+		return fmt.Errorf("refusing to cache non-JSON response")
+	}
+	if err := os.MkdirAll(d.Dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.Marshal(diskEntry{Key: key, Expires: time.Now().Add(ttl), Body: value})
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(d.Dir, ".entry-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return os.Rename(tmp.Name(), d.path(key))
+}
+
+// CacheStats summarizes the contents of a DiskCache
+type CacheStats struct {
+	Entries int   `json:"entries"`
+	Expired int   `json:"expired"`
+	Bytes   int64 `json:"bytes"`
+}
+
+// Stats walks the cache directory and counts its entries
+func (d *DiskCache) Stats() (CacheStats, error) {
+	var stats CacheStats
+	now := time.Now()
+	err := d.walk(func(path string, info fs.FileInfo) error {
+		stats.Entries++
+		stats.Bytes += info.Size()
+		if expired, _ := entryExpired(path, now); expired {
+			stats.Expired++
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// Clear removes cached entries. With expiredOnly, live entries are kept.
+// It returns the number of entries removed.
+func (d *DiskCache) Clear(expiredOnly bool) (int, error) {
+	removed := 0
+	now := time.Now()
+	err := d.walk(func(path string, info fs.FileInfo) error {
+		if expiredOnly {
+			if expired, _ := entryExpired(path, now); !expired {
+				return nil
+			}
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove cache entry: %w", err)
+		}
+		removed++
+		return nil
+	})
+	return removed, err
+}
+
+func (d *DiskCache) walk(fn func(path string, info fs.FileInfo) error) error {
+	entries, err := os.ReadDir(d.Dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read cache directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if err := fn(filepath.Join(d.Dir, entry.Name()), info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// entryExpired reports whether the entry at path has expired. Unreadable
+// entries count as expired.
+func entryExpired(path string, now time.Time) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return true, err
+	}
+	var entry struct {
+		Expires time.Time `json:"expires"`
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return true, err
+	}
+	return now.After(entry.Expires), nil
+}
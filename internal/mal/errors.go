@@ -0,0 +1,79 @@
+package mal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned when MAL responds with a non-success status code.
+// Error and Message are taken from MAL's JSON error body when present.
+type APIError struct {
+	StatusCode int
+	Err        string `json:"error"`
+	Message    string `json:"message"`
+	Method     string
+	URL        string
+	// Body holds the raw response when it was not a MAL error object
+	Body string
+}
+
+func (e *APIError) Error() string {
+	detail := e.Err
+	if e.Message != "" {
+		if detail != "" {
+			detail += ": "
+		}
+		detail += e.Message
+	}
+	if detail == "" {
+		detail = strings.TrimSpace(e.Body)
+	}
+	if detail == "" {
+		detail = http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("API returned status %d: %s", e.StatusCode, detail)
+}
+
+// newAPIError builds an APIError from a failed response, consuming its body
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	if resp.Request != nil {
+		apiErr.Method = resp.Request.Method
+		apiErr.URL = resp.Request.URL.String()
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err := json.Unmarshal(body, apiErr); err != nil || (apiErr.Err == "" && apiErr.Message == "") {
+		apiErr.Body = string(body)
+	}
+	return apiErr
+}
+
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// IsNotFound reports whether err is a 404 from MAL, e.g. an unknown ID
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsUnauthorized reports whether MAL rejected the client ID or access token
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsForbidden reports whether MAL refused access to the resource
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+// IsRateLimited reports whether MAL throttled the request
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
@@ -0,0 +1,103 @@
+package mal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AnimeFields lists the anime fields MAL accepts in the fields parameter
+var AnimeFields = []string{
+	"id",
+	"title",
+	"main_picture",
+	"alternative_titles",
+	"start_date",
+	"end_date",
+	"synopsis",
+	"mean",
+	"rank",
+	"popularity",
+	"num_list_users",
+	"num_scoring_users",
+	"nsfw",
+	"genres",
+	"created_at",
+	"updated_at",
+	"media_type",
+	"status",
+	"my_list_status",
+	"num_episodes",
+	"start_season",
+	"broadcast",
+	"source",
+	"average_episode_duration",
+	"rating",
+	"studios",
+	"pictures",
+	"background",
+	"related_anime",
+	"related_manga",
+	"recommendations",
+	"statistics",
+}
+
+const (
+	// DefaultAnimeDetailsFields is requested by Details when no fields are given
+	DefaultAnimeDetailsFields = "id,title,synopsis,num_episodes,status,start_date,end_date,mean,rank,popularity"
+	// DefaultAnimeListFields is requested by Search and Rankings when no
+	// fields are given
+	DefaultAnimeListFields = "alternative_titles"
+)
+
+// ValidateAnimeFields checks that every field is one MAL knows about.
+// Fields may carry a sub-field selection, e.g. "studios{name}".
+func ValidateAnimeFields(fields []string) error {
+	valid := make(map[string]bool, len(AnimeFields))
+	for _, f := range AnimeFields {
+		valid[f] = true
+	}
+	for _, field := range fields {
+		name, _, _ := strings.Cut(field, "{")
+		if !valid[strings.TrimSpace(name)] || strings.Count(field, "{") != strings.Count(field, "}") {
+			return fmt.Errorf("invalid anime field: %s", field)
+		}
+	}
+	return nil
+}
+
+// SplitFields splits a comma-separated field list at its top-level commas,
+// keeping sub-field selections like studios{id,name} whole
+func SplitFields(list string) []string {
+	var fields []string
+	depth, start := 0, 0
+	for i, r := range list {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				fields = appendField(fields, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return appendField(fields, list[start:])
+}
+
+func appendField(fields []string, field string) []string {
+	if field = strings.TrimSpace(field); field != "" {
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// fieldsParam joins fields for the fields query parameter, falling back to
+// defaults when none are given
+func fieldsParam(fields []string, defaults string) string {
+	if len(fields) == 0 {
+		return defaults
+	}
+	return strings.Join(fields, ",")
+}
@@ -0,0 +1,35 @@
+package mal
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitFields(t *testing.T) {
+	tests := []struct {
+		list string
+		want []string
+	}{
+		{"", nil},
+		{"genres,studios", []string{"genres", "studios"}},
+		{" genres , studios ,", []string{"genres", "studios"}},
+		{"studios{id,name},mean", []string{"studios{id,name}", "mean"}},
+		{"related_anime{node{id,title}},genres", []string{"related_anime{node{id,title}}", "genres"}},
+	}
+	for _, tt := range tests {
+		if got := SplitFields(tt.list); !slices.Equal(got, tt.want) {
+			t.Errorf("SplitFields(%q) = %q, want %q", tt.list, got, tt.want)
+		}
+	}
+}
+
+func TestValidateAnimeFieldsWithSubfields(t *testing.T) {
+	if err := ValidateAnimeFields(SplitFields("studios{id,name},related_anime{node{id,title}}")); err != nil {
+		t.Errorf("valid sub-field selection rejected: %v", err)
+	}
+	for _, list := range []string{"studios{id,name", "bogus{id}"} {
+		if err := ValidateAnimeFields(SplitFields(list)); err == nil {
+			t.Errorf("ValidateAnimeFields(%q) accepted an invalid field", list)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+package mal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// DefaultFranchiseDepth is how many relation hops Franchise follows unless
+// told otherwise
+const DefaultFranchiseDepth = 10
+
+// franchiseFields are requested for each anime in a franchise
+const franchiseFields = "alternative_titles,media_type,num_episodes,start_date,start_season,status,related_anime"
+
+// FranchiseRelations are the relation types Franchise follows by default:
+// the story itself and its side stories and alternative versions, but not
+// spin-offs, summaries or shared characters
+var FranchiseRelations = []string{
+	"sequel", "prequel", "side_story", "parent_story", "alternative_setting", "alternative_version",
+}
+
+// inverseRelations maps relations to the edge they describe from the other
+// side, so each link between two anime is recorded once
+var inverseRelations = map[string]string{
+	"prequel":      "sequel",
+	"parent_story": "side_story",
+	"full_story":   "summary",
+}
+
+// symmetricRelations describe the same link from either side
+var symmetricRelations = []string{"alternative_setting", "alternative_version", "other", "character", "spin_off"}
+
+// FranchiseOptions controls how Franchise crawls relations
+type FranchiseOptions struct {
+	// MaxDepth is how many hops from the starting anime to follow;
+	// 0 means DefaultFranchiseDepth
+	MaxDepth int
+	// Relations are the relation types to follow; nil means
+	// FranchiseRelations
+	Relations []string
+}
+
+// FranchiseEntry is one anime in a franchise
+type FranchiseEntry struct {
+	Anime AnimeDetails `json:"anime"`
+	// Depth is the number of hops from the starting anime
+	Depth int `json:"depth"`
+}
+
+// FranchiseLink is a relation between two anime in a franchise. Prequel
+// and parent story relations are stored reversed, as sequel and side story
+// links from the earlier anime.
+type FranchiseLink struct {
+	From         int    `json:"from"`
+	To           int    `json:"to"`
+	RelationType string `json:"relation_type"`
+}
+
+// Franchise is the relation graph around an anime
+type Franchise struct {
+	Root int `json:"root"`
+	// Entries are in the order they were reached
+	Entries []FranchiseEntry `json:"entries"`
+	Links   []FranchiseLink  `json:"links"`
+	// Truncated is set when the depth limit left relations unexplored
+	Truncated bool `json:"truncated"`
+	// Errors lists anime that could not be fetched
+	Errors []BatchError `json:"errors,omitempty"`
+}
+
+// Related returns the anime related to an anime, such as its sequels and
+// side stories
+func (a *AnimeService) Related(animeID int) ([]RelatedAnime, error) {
+	return a.RelatedContext(context.Background(), animeID)
+}
+
+// RelatedContext is like Related but uses ctx for the request
+func (a *AnimeService) RelatedContext(ctx context.Context, animeID int) ([]RelatedAnime, error) {
+	details, err := a.DetailsContext(ctx, animeID, "related_anime")
+	if err != nil {
+		return nil, err
+	}
+	return details.RelatedAnime, nil
+}
+
+// Franchise crawls the relations of an anime breadth-first, fetching each
+// level of the graph as a batch. Every anime is fetched once, however many
+// relations lead to it.
+func (a *AnimeService) Franchise(animeID int, opts FranchiseOptions) (*Franchise, error) {
+	return a.FranchiseContext(context.Background(), animeID, opts)
+}
+
+// FranchiseContext is like Franchise but uses ctx for its requests
+func (a *AnimeService) FranchiseContext(ctx context.Context, animeID int, opts FranchiseOptions) (*Franchise, error) {
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = DefaultFranchiseDepth
+	}
+	if opts.Relations == nil {
+		opts.Relations = FranchiseRelations
+	}
+
+	root, err := a.DetailsContext(ctx, animeID, franchiseFields)
+	if err != nil {
+		return nil, err
+	}
+
+	franchise := &Franchise{Root: animeID}
+	seen := map[int]bool{animeID: true}
+	links := make(map[FranchiseLink]bool)
+	level := []AnimeDetails{*root}
+
+	for depth := 0; len(level) > 0; depth++ {
+		var next []int
+		for _, anime := range level {
+			franchise.Entries = append(franchise.Entries, FranchiseEntry{Anime: anime, Depth: depth})
+			for _, related := range anime.RelatedAnime {
+				if !slices.Contains(opts.Relations, related.RelationType) {
+					continue
+				}
+				links[canonicalLink(anime.ID, related.Node.ID, related.RelationType)] = true
+				if seen[related.Node.ID] {
+					continue
+				}
+				if depth == opts.MaxDepth {
+					franchise.Truncated = true
+					continue
+				}
+				seen[related.Node.ID] = true
+				next = append(next, related.Node.ID)
+			}
+			// Related anime are not needed once the links are recorded
+			franchise.Entries[len(franchise.Entries)-1].Anime.RelatedAnime = nil
+		}
+		if len(next) == 0 {
+			break
+		}
+
+		batch, err := a.BatchDetailsContext(ctx, next, franchiseFields)
+		if err != nil {
+			return nil, err
+		}
+		franchise.Errors = append(franchise.Errors, batch.Errors...)
+		level = batch.Results
+	}
+
+	// Keep only links between anime that made it into the graph
+	included := make(map[int]bool, len(franchise.Entries))
+	for _, entry := range franchise.Entries {
+		included[entry.Anime.ID] = true
+	}
+	for link := range links {
+		if included[link.From] && included[link.To] {
+			franchise.Links = append(franchise.Links, link)
+		}
+	}
+	sort.Slice(franchise.Links, func(i, j int) bool {
+		a, b := franchise.Links[i], franchise.Links[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.RelationType < b.RelationType
+	})
+	return franchise, nil
+}
+
+// canonicalLink records the relation "to is <relation> of from" in the
+// direction shared by both sides of the link
+func canonicalLink(from, to int, relation string) FranchiseLink {
+	if inverse, ok := inverseRelations[relation]; ok {
+		return FranchiseLink{From: to, To: from, RelationType: inverse}
+	}
+	if slices.Contains(symmetricRelations, relation) && to < from {
+		from, to = to, from
+	}
+	return FranchiseLink{From: from, To: to, RelationType: relation}
+}
+
+// WatchOrder returns the franchise's entries in story order: every anime
+// comes after its prequels and after the story it is a side story of.
+// Otherwise anime are ordered by release, with undated anime last.
+func (f *Franchise) WatchOrder() []FranchiseEntry {
+	// before counts the unplaced anime that must come first
+	before := make(map[int]int, len(f.Entries))
+	after := make(map[int][]int)
+	for _, link := range f.Links {
+		if link.RelationType == "sequel" || link.RelationType == "side_story" {
+			before[link.To]++
+			after[link.From] = append(after[link.From], link.To)
+		}
+	}
+
+	remaining := slices.Clone(f.Entries)
+	sort.SliceStable(remaining, func(i, j int) bool {
+		return releasedBefore(&remaining[i].Anime, &remaining[j].Anime)
+	})
+
+	order := make([]FranchiseEntry, 0, len(remaining))
+	for len(remaining) > 0 {
+		// Take the earliest anime that is ready; if relations form a cycle,
+		// nothing is ready and the earliest overall breaks it
+		next := 0
+		for i, entry := range remaining {
+			if before[entry.Anime.ID] == 0 {
+				next = i
+				break
+			}
+		}
+		entry := remaining[next]
+		remaining = slices.Delete(remaining, next, next+1)
+		order = append(order, entry)
+		for _, id := range after[entry.Anime.ID] {
+			before[id]--
+		}
+	}
+	return order
+}
+
+// releasedBefore orders anime by start date, undated last, then by ID
+func releasedBefore(a, b *AnimeDetails) bool {
+	switch {
+	case (a.StartDate == "") != (b.StartDate == ""):
+		return b.StartDate == ""
+	case a.StartDate != b.StartDate:
+		// MAL dates are YYYY, YYYY-MM or YYYY-MM-DD, which sort as text
+		return a.StartDate < b.StartDate
+	}
+	return a.ID < b.ID
+}
+
+// WriteDOT writes the relation graph in Graphviz DOT format, labelling
+// each anime with its title in lang
+func (f *Franchise) WriteDOT(w io.Writer, lang TitleLanguage) error {
+	var b strings.Builder
+	b.WriteString("digraph franchise {\n")
+	b.WriteString("  rankdir=LR;\n  node [shape=box];\n")
+	for _, entry := range f.Entries {
+		attrs := fmt.Sprintf("label=%s", dotQuote(graphLabel(&entry.Anime, lang)))
+		if entry.Anime.ID == f.Root {
+			attrs += ", style=bold"
+		}
+		fmt.Fprintf(&b, "  a%d [%s];\n", entry.Anime.ID, attrs)
+	}
+	for _, link := range f.Links {
+		attrs := fmt.Sprintf("label=%s", dotQuote(formatRelation(link.RelationType)))
+		if link.RelationType != "sequel" {
+			attrs += ", style=dashed"
+		}
+		if slices.Contains(symmetricRelations, link.RelationType) {
+			attrs += ", dir=none"
+		}
+		fmt.Fprintf(&b, "  a%d -> a%d [%s];\n", link.From, link.To, attrs)
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid writes the relation graph as a Mermaid flowchart, labelling
+// each anime with its title in lang
+func (f *Franchise) WriteMermaid(w io.Writer, lang TitleLanguage) error {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for _, entry := range f.Entries {
+		label := mermaidQuote(graphLabel(&entry.Anime, lang))
+		if entry.Anime.ID == f.Root {
+			fmt.Fprintf(&b, "  a%d[[%s]]\n", entry.Anime.ID, label)
+		} else {
+			fmt.Fprintf(&b, "  a%d[%s]\n", entry.Anime.ID, label)
+		}
+	}
+	for _, link := range f.Links {
+		arrow := "-.->"
+		switch {
+		case link.RelationType == "sequel":
+			arrow = "-->"
+		case slices.Contains(symmetricRelations, link.RelationType):
+			arrow = "-.-"
+		}
+		fmt.Fprintf(&b, "  a%d %s|%s| a%d\n", link.From, arrow, mermaidQuote(formatRelation(link.RelationType)), link.To)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// graphLabel names an anime in an exported graph, e.g.
+// "Bakemonogatari (tv, 2009)"
+func graphLabel(anime *AnimeDetails, lang TitleLanguage) string {
+	var details []string
+	if anime.MediaType != "" {
+		details = append(details, anime.MediaType)
+	}
+	if len(anime.StartDate) >= 4 {
+		details = append(details, anime.StartDate[:4])
+	}
+	if len(details) == 0 {
+		return anime.PreferredTitle(lang)
+	}
+	return fmt.Sprintf("%s (%s)", anime.PreferredTitle(lang), strings.Join(details, ", "))
+}
+
+func formatRelation(relation string) string {
+	return strings.ReplaceAll(relation, "_", " ")
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// mermaidQuote quotes a label, using Mermaid's entity codes for characters
+// that would end it
+func mermaidQuote(s string) string {
+	return `"` + strings.NewReplacer(`"`, "#quot;").Replace(s) + `"`
+}
@@ -0,0 +1,259 @@
+package mal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// relationGraph serves anime details, with their related anime, from a
+// fixed graph and counts how often each anime is fetched
+type relationGraph struct {
+	*httptest.Server
+	anime map[int]*AnimeDetails
+
+	mu      sync.Mutex
+	fetched map[int]int
+}
+
+func newRelationGraph(t *testing.T) *relationGraph {
+	t.Helper()
+	g := &relationGraph{anime: make(map[int]*AnimeDetails), fetched: make(map[int]int)}
+	g.Server = httptest.NewServer(http.HandlerFunc(g.serve))
+	t.Cleanup(g.Close)
+	return g
+}
+
+func (g *relationGraph) add(id int, title, startDate string) {
+	g.anime[id] = &AnimeDetails{ID: id, Title: title, StartDate: startDate}
+}
+
+// relate records that to is relation of from, as MAL lists it on from
+func (g *relationGraph) relate(from, to int, relation string) {
+	g.anime[from].RelatedAnime = append(g.anime[from].RelatedAnime, RelatedAnime{
+		Node:         AnimeNode{ID: to, Title: g.anime[to].Title},
+		RelationType: relation,
+	})
+}
+
+func (g *relationGraph) serve(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/anime/"))
+	anime, ok := g.anime[id]
+	if err != nil || !ok {
+		http.NotFound(w, r)
+		return
+	}
+	g.mu.Lock()
+	g.fetched[id]++
+	g.mu.Unlock()
+	json.NewEncoder(w).Encode(anime)
+}
+
+func (g *relationGraph) client(t *testing.T) *Client {
+	t.Helper()
+	client := NewClient(g.Client(), "client-id")
+	if err := client.SetBaseURL(g.URL); err != nil {
+		t.Fatal(err)
+	}
+	client.SetRetryPolicy(RetryPolicy{})
+	return client
+}
+
+// newShowGraph builds a four-season show with a side story, an alternative
+// version of the last season and a spin-off:
+//
+//	1 → 2 → 3 → 4 ~ 30
+//	↓       ⋮
+//	10      20 (spin-off)
+//
+// Every relation is listed from both sides, as MAL does.
+func newShowGraph(t *testing.T) *relationGraph {
+	g := newRelationGraph(t)
+	g.add(1, "Show", "2010-04")
+	g.add(2, "Show 2nd Season", "2012-01")
+	g.add(3, "Show 3rd Season", "2014-07")
+	g.add(4, "Show Final Season", "2016-10")
+	// The side story aired before the season it belongs to
+	g.add(10, "Show OVA", "2009-12")
+	g.add(20, "Show Spin-off", "2015")
+	g.add(30, "Show Final Season (Director's Cut)", "2017")
+
+	for _, pair := range [][2]int{{1, 2}, {2, 3}, {3, 4}} {
+		g.relate(pair[0], pair[1], "sequel")
+		g.relate(pair[1], pair[0], "prequel")
+	}
+	g.relate(1, 10, "side_story")
+	g.relate(10, 1, "parent_story")
+	g.relate(3, 20, "spin_off")
+	g.relate(20, 3, "spin_off")
+	g.relate(4, 30, "alternative_version")
+	g.relate(30, 4, "alternative_version")
+	return g
+}
+
+func entryDepths(f *Franchise) map[int]int {
+	depths := make(map[int]int, len(f.Entries))
+	for _, entry := range f.Entries {
+		depths[entry.Anime.ID] = entry.Depth
+	}
+	return depths
+}
+
+func TestFranchise(t *testing.T) {
+	g := newShowGraph(t)
+
+	franchise, err := g.client(t).Anime.FranchiseContext(context.Background(), 2, FranchiseOptions{})
+	if err != nil {
+		t.Fatalf("FranchiseContext: %v", err)
+	}
+
+	wantDepths := map[int]int{2: 0, 1: 1, 3: 1, 10: 2, 4: 2, 30: 3}
+	if got := entryDepths(franchise); !reflect.DeepEqual(got, wantDepths) {
+		t.Errorf("entry depths = %v, want %v", got, wantDepths)
+	}
+	// Each link appears once, in its canonical direction; the spin-off is
+	// not followed by default
+	wantLinks := []FranchiseLink{
+		{From: 1, To: 2, RelationType: "sequel"},
+		{From: 1, To: 10, RelationType: "side_story"},
+		{From: 2, To: 3, RelationType: "sequel"},
+		{From: 3, To: 4, RelationType: "sequel"},
+		{From: 4, To: 30, RelationType: "alternative_version"},
+	}
+	if !reflect.DeepEqual(franchise.Links, wantLinks) {
+		t.Errorf("links = %+v, want %+v", franchise.Links, wantLinks)
+	}
+	if franchise.Truncated {
+		t.Error("Truncated is set although the whole graph was explored")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for id, n := range g.fetched {
+		if n != 1 {
+			t.Errorf("anime %d fetched %d times, want once", id, n)
+		}
+	}
+	if g.fetched[20] != 0 {
+		t.Error("the spin-off was fetched")
+	}
+}
+
+func TestFranchiseFollowsChosenRelations(t *testing.T) {
+	g := newShowGraph(t)
+
+	franchise, err := g.client(t).Anime.FranchiseContext(context.Background(), 3, FranchiseOptions{
+		Relations: []string{"spin_off"},
+	})
+	if err != nil {
+		t.Fatalf("FranchiseContext: %v", err)
+	}
+	if got, want := entryDepths(franchise), map[int]int{3: 0, 20: 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("entry depths = %v, want %v", got, want)
+	}
+	want := []FranchiseLink{{From: 3, To: 20, RelationType: "spin_off"}}
+	if !reflect.DeepEqual(franchise.Links, want) {
+		t.Errorf("links = %+v, want %+v", franchise.Links, want)
+	}
+}
+
+func TestFranchiseTruncatedAtMaxDepth(t *testing.T) {
+	g := newShowGraph(t)
+
+	franchise, err := g.client(t).Anime.FranchiseContext(context.Background(), 2, FranchiseOptions{MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("FranchiseContext: %v", err)
+	}
+	if got, want := entryDepths(franchise), map[int]int{2: 0, 1: 1, 3: 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("entry depths = %v, want %v", got, want)
+	}
+	if !franchise.Truncated {
+		t.Error("Truncated is not set although 10 and 4 were left out")
+	}
+	// Links to anime beyond the limit are dropped
+	want := []FranchiseLink{
+		{From: 1, To: 2, RelationType: "sequel"},
+		{From: 2, To: 3, RelationType: "sequel"},
+	}
+	if !reflect.DeepEqual(franchise.Links, want) {
+		t.Errorf("links = %+v, want %+v", franchise.Links, want)
+	}
+
+	// A graph that ends exactly at the limit is not truncated
+	franchise, err = g.client(t).Anime.FranchiseContext(context.Background(), 10, FranchiseOptions{MaxDepth: 5})
+	if err != nil {
+		t.Fatalf("FranchiseContext: %v", err)
+	}
+	if franchise.Truncated {
+		t.Errorf("Truncated is set with every anime within %d hops", 5)
+	}
+}
+
+func TestCanonicalLink(t *testing.T) {
+	tests := []struct {
+		from, to int
+		relation string
+		want     FranchiseLink
+	}{
+		{1, 2, "sequel", FranchiseLink{From: 1, To: 2, RelationType: "sequel"}},
+		{2, 1, "prequel", FranchiseLink{From: 1, To: 2, RelationType: "sequel"}},
+		{1, 10, "side_story", FranchiseLink{From: 1, To: 10, RelationType: "side_story"}},
+		{10, 1, "parent_story", FranchiseLink{From: 1, To: 10, RelationType: "side_story"}},
+		{5, 9, "full_story", FranchiseLink{From: 9, To: 5, RelationType: "summary"}},
+		{30, 4, "alternative_version", FranchiseLink{From: 4, To: 30, RelationType: "alternative_version"}},
+		{4, 30, "alternative_version", FranchiseLink{From: 4, To: 30, RelationType: "alternative_version"}},
+		{8, 3, "alternative_setting", FranchiseLink{From: 3, To: 8, RelationType: "alternative_setting"}},
+	}
+	for _, tt := range tests {
+		if got := canonicalLink(tt.from, tt.to, tt.relation); got != tt.want {
+			t.Errorf("canonicalLink(%d, %d, %q) = %+v, want %+v", tt.from, tt.to, tt.relation, got, tt.want)
+		}
+	}
+}
+
+func watchOrderIDs(f *Franchise) []int {
+	var ids []int
+	for _, entry := range f.WatchOrder() {
+		ids = append(ids, entry.Anime.ID)
+	}
+	return ids
+}
+
+func TestWatchOrder(t *testing.T) {
+	g := newShowGraph(t)
+
+	franchise, err := g.client(t).Anime.FranchiseContext(context.Background(), 4, FranchiseOptions{})
+	if err != nil {
+		t.Fatalf("FranchiseContext: %v", err)
+	}
+	// The OVA aired first but comes after the story it is a side story of
+	want := []int{1, 10, 2, 3, 4, 30}
+	if got := watchOrderIDs(franchise); !reflect.DeepEqual(got, want) {
+		t.Errorf("watch order = %v, want %v", got, want)
+	}
+}
+
+func TestWatchOrderBreaksCycles(t *testing.T) {
+	entry := func(id int, startDate string) FranchiseEntry {
+		return FranchiseEntry{Anime: AnimeDetails{ID: id, StartDate: startDate}}
+	}
+	franchise := &Franchise{
+		Entries: []FranchiseEntry{entry(3, "2014"), entry(1, "2010"), entry(2, "2012")},
+		Links: []FranchiseLink{
+			{From: 1, To: 2, RelationType: "sequel"},
+			{From: 2, To: 3, RelationType: "sequel"},
+			{From: 3, To: 1, RelationType: "sequel"},
+		},
+	}
+	// Nothing is ready, so the earliest anime breaks the cycle
+	want := []int{1, 2, 3}
+	if got := watchOrderIDs(franchise); !reflect.DeepEqual(got, want) {
+		t.Errorf("watch order = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,329 @@
+package mal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// UserListService reads and updates users' anime lists. Updates and
+// deletions require an access token.
+type UserListService struct {
+	client *Client
+}
+
+// ErrNotOnList is returned when deleting an anime that is not on the user's list
+var ErrNotOnList = errors.New("anime is not on your list")
+
+// AnimeListStatus is a user's progress and rating for one anime
+type AnimeListStatus struct {
+	Status             string   `json:"status"`
+	Score              int      `json:"score"`
+	NumEpisodesWatched int      `json:"num_episodes_watched"`
+	IsRewatching       bool     `json:"is_rewatching"`
+	StartDate          string   `json:"start_date,omitempty"`
+	FinishDate         string   `json:"finish_date,omitempty"`
+	Priority           int      `json:"priority,omitempty"`
+	NumTimesRewatched  int      `json:"num_times_rewatched,omitempty"`
+	RewatchValue       int      `json:"rewatch_value,omitempty"`
+	Tags               []string `json:"tags,omitempty"`
+	Comments           string   `json:"comments,omitempty"`
+	UpdatedAt          string   `json:"updated_at,omitempty"`
+}
+
+// AnimeListEntry is one anime on a user's list
+type AnimeListEntry struct {
+	Node       AnimeDetails    `json:"node"`
+	ListStatus AnimeListStatus `json:"list_status"`
+}
+
+type AnimeListResponse struct {
+	Data   []AnimeListEntry `json:"data"`
+	Paging Paging           `json:"paging"`
+}
+
+// AnimeListStatusFields selects every list status field, including tags
+// and comments, for AnimeListOptions.Fields
+const AnimeListStatusFields = "list_status{status,score,num_episodes_watched,is_rewatching,start_date,finish_date,priority,num_times_rewatched,rewatch_value,tags,comments,updated_at}"
+
+// AnimeListOptions filters and orders a user's anime list. Zero values are
+// left to MAL's defaults.
+type AnimeListOptions struct {
+	Status string
+	Sort   string
+	Limit  int
+	Offset int
+	// Fields are anime fields to request in addition to the list status,
+	// episode count and alternative titles. A list_status{...} selection
+	// replaces the default list status fields.
+	Fields []string
+}
+
+// AnimeListUpdate holds the fields to change on a list entry. Nil fields are
+// left untouched.
+type AnimeListUpdate struct {
+	Status             *string
+	Score              *int
+	NumWatchedEpisodes *int
+	IsRewatching       *bool
+	NumTimesRewatched  *int
+	Priority           *int
+	StartDate          *string
+	FinishDate         *string
+	Tags               *[]string
+	Comments           *string
+}
+
+// Validate checks the update against MAL's accepted values
+func (u AnimeListUpdate) Validate() error {
+	if u.Status != nil {
+		if err := ValidateAnimeListStatus(*u.Status); err != nil {
+			return err
+		}
+	}
+	if u.Score != nil {
+		if err := ValidateScore(*u.Score); err != nil {
+			return err
+		}
+	}
+	if u.NumWatchedEpisodes != nil && *u.NumWatchedEpisodes < 0 {
+		return fmt.Errorf("episodes watched cannot be negative, got %d", *u.NumWatchedEpisodes)
+	}
+	if u.NumTimesRewatched != nil && *u.NumTimesRewatched < 0 {
+		return fmt.Errorf("times rewatched cannot be negative, got %d", *u.NumTimesRewatched)
+	}
+	if u.Priority != nil && (*u.Priority < 0 || *u.Priority > 2) {
+		return fmt.Errorf("priority must be between 0 and 2, got %d", *u.Priority)
+	}
+	return nil
+}
+
+func (u AnimeListUpdate) values() url.Values {
+	form := url.Values{}
+	if u.Status != nil {
+		form.Set("status", *u.Status)
+	}
+	if u.Score != nil {
+		form.Set("score", strconv.Itoa(*u.Score))
+	}
+	if u.NumWatchedEpisodes != nil {
+		form.Set("num_watched_episodes", strconv.Itoa(*u.NumWatchedEpisodes))
+	}
+	if u.IsRewatching != nil {
+		form.Set("is_rewatching", strconv.FormatBool(*u.IsRewatching))
+	}
+	if u.NumTimesRewatched != nil {
+		form.Set("num_times_rewatched", strconv.Itoa(*u.NumTimesRewatched))
+	}
+	if u.Priority != nil {
+		form.Set("priority", strconv.Itoa(*u.Priority))
+	}
+	if u.StartDate != nil {
+		form.Set("start_date", *u.StartDate)
+	}
+	if u.FinishDate != nil {
+		form.Set("finish_date", *u.FinishDate)
+	}
+	if u.Tags != nil {
+		form.Set("tags", strings.Join(*u.Tags, ","))
+	}
+	if u.Comments != nil {
+		form.Set("comments", *u.Comments)
+	}
+	return form
+}
+
+// AnimeList returns a page of a user's anime list. Use "@me" for the
+// logged-in user.
+func (l *UserListService) AnimeList(userName string, opts AnimeListOptions) (*AnimeListResponse, error) {
+	return l.AnimeListContext(context.Background(), userName, opts)
+}
+
+// AnimeListContext is like AnimeList but uses ctx for the request
+func (l *UserListService) AnimeListContext(ctx context.Context, userName string, opts AnimeListOptions) (*AnimeListResponse, error) {
+	params := url.Values{}
+	params.Add("fields", listFields([]string{"list_status", "num_episodes", "alternative_titles"}, opts.Fields))
+	if opts.Status != "" {
+		params.Add("status", opts.Status)
+	}
+	if opts.Sort != "" {
+		params.Add("sort", opts.Sort)
+	}
+	if opts.Limit > 0 {
+		params.Add("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Offset > 0 {
+		params.Add("offset", strconv.Itoa(opts.Offset))
+	}
+	if l.client.nsfw {
+		params.Add("nsfw", "true")
+	}
+
+	reqURL := l.client.baseURL.String() + "users/" + url.PathEscape(userName) + "/animelist?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var list AnimeListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	l.client.recordAnime(ctx, &list)
+
+	return &list, nil
+}
+
+// AnimeStatus returns the logged-in user's list status for an anime, or nil
+// if the anime is not on their list
+func (l *UserListService) AnimeStatus(animeID int) (*AnimeListStatus, error) {
+	return l.AnimeStatusContext(context.Background(), animeID)
+}
+
+// AnimeStatusContext is like AnimeStatus but uses ctx for the request
+func (l *UserListService) AnimeStatusContext(ctx context.Context, animeID int) (*AnimeListStatus, error) {
+	reqURL := l.client.baseURL.String() + "anime/" + strconv.Itoa(animeID) + "?fields=my_list_status"
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var result struct {
+		MyListStatus *AnimeListStatus `json:"my_list_status"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.MyListStatus, nil
+}
+
+// UpdateAnime adds an anime to the logged-in user's list or changes its
+// entry, returning the entry as stored by MAL
+func (l *UserListService) UpdateAnime(animeID int, update AnimeListUpdate) (*AnimeListStatus, error) {
+	return l.UpdateAnimeContext(context.Background(), animeID, update)
+}
+
+// UpdateAnimeContext is like UpdateAnime but uses ctx for the request
+func (l *UserListService) UpdateAnimeContext(ctx context.Context, animeID int, update AnimeListUpdate) (*AnimeListStatus, error) {
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+
+	reqURL := l.client.baseURL.String() + "anime/" + strconv.Itoa(animeID) + "/my_list_status"
+
+	req, err := http.NewRequestWithContext(ctx, "PATCH", reqURL, strings.NewReader(update.values().Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var status AnimeListStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &status, nil
+}
+
+// DeleteAnime removes an anime from the logged-in user's list
+func (l *UserListService) DeleteAnime(animeID int) error {
+	return l.DeleteAnimeContext(context.Background(), animeID)
+}
+
+// DeleteAnimeContext is like DeleteAnime but uses ctx for the request
+func (l *UserListService) DeleteAnimeContext(ctx context.Context, animeID int) error {
+	reqURL := l.client.baseURL.String() + "anime/" + strconv.Itoa(animeID) + "/my_list_status"
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return ErrNotOnList
+	default:
+		return newAPIError(resp)
+	}
+}
+
+func ValidateAnimeListStatus(status string) error {
+	validStatuses := map[string]bool{
+		"watching":      true,
+		"completed":     true,
+		"on_hold":       true,
+		"dropped":       true,
+		"plan_to_watch": true,
+	}
+	if !validStatuses[status] {
+		return fmt.Errorf("invalid list status: %s", status)
+	}
+	return nil
+}
+
+func ValidateAnimeListSort(sort string) error {
+	validSorts := map[string]bool{
+		"list_score":       true,
+		"list_updated_at":  true,
+		"anime_title":      true,
+		"anime_start_date": true,
+	}
+	if !validSorts[sort] {
+		return fmt.Errorf("invalid list sort: %s", sort)
+	}
+	return nil
+}
+
+// ValidateScore checks a list score, where 0 means "no score"
+func ValidateScore(score int) error {
+	if score < 0 || score > 10 {
+		return fmt.Errorf("score must be between 0 and 10, got %d", score)
+	}
+	return nil
+}
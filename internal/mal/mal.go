@@ -1,19 +1,41 @@
 package mal
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/time/rate"
 )
 
 const malURL = "https://api.myanimelist.net/v2/"
 
 type Client struct {
-	client   *http.Client
-	baseURL  *url.URL
-	clientID string
+	client      *http.Client
+	baseURL     *url.URL
+	clientID    string
+	tokenSource oauth2.TokenSource
+	timeout     time.Duration
+	retry       RetryPolicy
+	limiter     *rate.Limiter
+
+	batchConcurrency int
+	nsfw             bool
+
+	cache       Cache
+	cachePolicy CachePolicy
+	recorder    AnimeRecorder
 
 	Anime *AnimeService
+	Manga *MangaService
+	User  *UserService
+	List  *UserListService
 }
 
 func NewClient(httpClient *http.Client, clientID string) *Client {
@@ -31,14 +53,156 @@ func NewClient(httpClient *http.Client, clientID string) *Client {
 		client:   httpClient,
 		baseURL:  baseURL,
 		clientID: clientID,
+		retry:    DefaultRetryPolicy,
+
+		batchConcurrency: DefaultBatchConcurrency,
 	}
 	c.Anime = &AnimeService{client: c}
+	c.Manga = &MangaService{client: c}
+	c.User = &UserService{client: c}
+	c.List = &UserListService{client: c}
 
 	return c
 }
 
-// Do sends an HTTP request and adds the MAL client ID header
+// SetNSFW controls whether search, ranking, seasonal and list results
+// include entries MAL marks as not safe for work. They are hidden by default.
+func (c *Client) SetNSFW(include bool) {
+	c.nsfw = include
+}
+
+// SetBaseURL points the client at a different API root, e.g. a test server
+func (c *Client) SetBaseURL(baseURL string) error {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid base URL: %w", err)
+	}
+	if len(u.Path) == 0 || u.Path[len(u.Path)-1] != '/' {
+		u.Path += "/"
+	}
+	c.baseURL = u
+	return nil
+}
+
+// SetTokenSource enables authenticated requests. When a token is available
+// it is sent as a Bearer token alongside the client ID header.
+func (c *Client) SetTokenSource(ts oauth2.TokenSource) {
+	c.tokenSource = ts
+}
+
+// SetRequestTimeout bounds each request, including reading its body.
+// Zero disables the deadline; the request context still applies.
+func (c *Client) SetRequestTimeout(timeout time.Duration) {
+	c.timeout = timeout
+}
+
+// Do sends an HTTP request and adds the MAL client ID header, plus the
+// user's access token when logged in. Cacheable GET requests are answered
+// from the client's cache when possible. Requests wait for the client's
+// rate limiter and are retried according to its RetryPolicy.
 func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	key := req.URL.String()
+	ttl := c.cacheTTL(req)
+	if ttl > 0 && !c.cachePolicy.Refresh {
+		if body, ok := c.cache.Get(key); ok {
+			return cachedResponse(req, body), nil
+		}
+	}
+
+	resp, err := c.do(req)
+	if err != nil || ttl <= 0 || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	if err := c.storeResponse(resp, key, ttl); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// do sends req to MAL, applying authentication, rate limiting and retries
+func (c *Client) do(req *http.Request) (*http.Response, error) {
 	req.Header.Set("X-MAL-CLIENT-ID", c.clientID)
-	return c.client.Do(req)
+	// Most endpoints only need the client ID, so a token that cannot be
+	// loaded or refreshed is only reported if MAL asks for one
+	var tokenErr error
+	if c.tokenSource != nil {
+		token, err := c.tokenSource.Token()
+		switch {
+		case err == nil:
+			token.SetAuthHeader(req)
+		case !errors.Is(err, ErrNotLoggedIn):
+			tokenErr = err
+		}
+	}
+
+	resp, err := c.retryDo(req)
+	if tokenErr != nil && err == nil && resp.StatusCode == http.StatusUnauthorized {
+		apiErr := newAPIError(resp)
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: %w", tokenErr, apiErr)
+	}
+	return resp, err
+}
+
+// retryDo sends req, applying rate limiting and retries
+func (c *Client) retryDo(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	var waited time.Duration
+	for attempt := 0; ; attempt++ {
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := c.send(req)
+		if attempt >= c.retry.MaxRetries || !shouldRetry(ctx, resp, err) {
+			return resp, err
+		}
+
+		next, ok := rewind(req)
+		if !ok {
+			return resp, err
+		}
+		wait := c.retry.backoff(attempt, resp)
+		if c.retry.MaxElapsed > 0 && waited+wait > c.retry.MaxElapsed {
+			return resp, err
+		}
+		if resp != nil {
+			drain(resp)
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+		waited += wait
+		req = next
+	}
+}
+
+// send performs a single attempt, applying the per-request timeout
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if c.timeout <= 0 {
+		return c.client.Do(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), c.timeout)
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// The deadline has to outlive Do so callers can still read the body
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
 }
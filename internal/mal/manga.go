@@ -0,0 +1,235 @@
+package mal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type MangaService struct {
+	client *Client
+}
+
+// MangaSearchResponse represents the MAL API response structure for manga search
+type MangaSearchResponse struct {
+	Data   []MangaData `json:"data"`
+	Paging Paging      `json:"paging"`
+}
+
+// MangaData represents individual manga items in the response
+type MangaData struct {
+	Node MangaNode `json:"node"`
+}
+
+// MangaNode contains the actual manga information
+type MangaNode struct {
+	ID                int               `json:"id"`
+	Title             string            `json:"title"`
+	MainPicture       Picture           `json:"main_picture,omitempty"`
+	AlternativeTitles AlternativeTitles `json:"alternative_titles,omitempty"`
+}
+
+type MangaDetails struct {
+	ID                int               `json:"id"`
+	Title             string            `json:"title"`
+	StartDate         string            `json:"start_date,omitempty"`
+	EndDate           string            `json:"end_date,omitempty"`
+	Mean              float64           `json:"mean,omitempty"`
+	Rank              int               `json:"rank,omitempty"`
+	Popularity        int               `json:"popularity,omitempty"`
+	Status            string            `json:"status,omitempty"`
+	MediaType         string            `json:"media_type,omitempty"`
+	NumVolumes        int               `json:"num_volumes"`
+	NumChapters       int               `json:"num_chapters"`
+	Synopsis          string            `json:"synopsis,omitempty"`
+	Genres            []Genre           `json:"genres,omitempty"`
+	Authors           []MangaAuthor     `json:"authors,omitempty"`
+	Serialization     []Serialization   `json:"serialization,omitempty"`
+	AlternativeTitles AlternativeTitles `json:"alternative_titles,omitempty"`
+	UpdatedAt         string            `json:"updated_at,omitempty"`
+}
+
+// Genre is a MAL genre or theme tag
+type Genre struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// MangaAuthor pairs a person with their role on a manga (e.g. "Story & Art")
+type MangaAuthor struct {
+	Node Person `json:"node"`
+	Role string `json:"role"`
+}
+
+// Person contains a MAL person's name
+type Person struct {
+	ID        int    `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// FullName returns the person's name in "First Last" order
+func (p Person) FullName() string {
+	if p.FirstName == "" {
+		return p.LastName
+	}
+	if p.LastName == "" {
+		return p.FirstName
+	}
+	return p.FirstName + " " + p.LastName
+}
+
+// Serialization is the magazine a manga was published in
+type Serialization struct {
+	Node Magazine `json:"node"`
+}
+
+type Magazine struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type MangaRankingData struct {
+	Node    MangaNode `json:"node"`
+	Ranking Ranking   `json:"ranking"`
+}
+
+type MangaRankingResponse struct {
+	Data   []MangaRankingData `json:"data"`
+	Paging Paging             `json:"paging"`
+}
+
+func (m *MangaService) Search(query string, limit int) (*MangaSearchResponse, error) {
+	return m.SearchContext(context.Background(), query, limit)
+}
+
+// SearchContext is like Search but uses ctx for the request
+func (m *MangaService) SearchContext(ctx context.Context, query string, limit int) (*MangaSearchResponse, error) {
+	params := url.Values{}
+	params.Add("q", query)
+	params.Add("limit", fmt.Sprintf("%d", limit))
+	params.Add("fields", "alternative_titles")
+	if m.client.nsfw {
+		params.Add("nsfw", "true")
+	}
+
+	reqURL := m.client.baseURL.String() + "manga?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var searchResponse MangaSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&searchResponse); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &searchResponse, nil
+}
+
+func (m *MangaService) Details(mangaID int) (*MangaDetails, error) {
+	return m.DetailsContext(context.Background(), mangaID)
+}
+
+// DetailsContext is like Details but uses ctx for the request
+func (m *MangaService) DetailsContext(ctx context.Context, mangaID int) (*MangaDetails, error) {
+	field := "id,title,alternative_titles,synopsis,num_volumes,num_chapters,status,media_type,start_date,end_date,mean,rank,popularity,genres,authors{first_name,last_name},serialization{name},updated_at"
+	reqURL := m.client.baseURL.String() + "manga/" + fmt.Sprintf("%d", mangaID) + "?fields=" + url.QueryEscape(field)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var details MangaDetails
+	if err := json.NewDecoder(resp.Body).Decode(&details); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &details, nil
+}
+
+// BatchDetails fetches details for several manga at once. Repeated IDs are
+// fetched once. IDs that fail are reported in the result's Errors rather than
+// failing the whole batch.
+func (m *MangaService) BatchDetails(mangaIDs []int) (*BatchResult[MangaDetails], error) {
+	return m.BatchDetailsContext(context.Background(), mangaIDs)
+}
+
+// BatchDetailsContext is like BatchDetails but uses ctx for its requests
+func (m *MangaService) BatchDetailsContext(ctx context.Context, mangaIDs []int) (*BatchResult[MangaDetails], error) {
+	return batchFetch(ctx, mangaIDs, m.client.batchConcurrency, m.DetailsContext)
+}
+
+func (m *MangaService) Rankings(rankingType string, limit, offset int) (*MangaRankingResponse, error) {
+	return m.RankingsContext(context.Background(), rankingType, limit, offset)
+}
+
+// RankingsContext is like Rankings but uses ctx for the request
+func (m *MangaService) RankingsContext(ctx context.Context, rankingType string, limit, offset int) (*MangaRankingResponse, error) {
+	reqURL := m.client.baseURL.String() + "manga/ranking?ranking_type=" + rankingType + fmt.Sprintf("&limit=%d&offset=%d", limit, offset) +
+		"&fields=alternative_titles"
+	if m.client.nsfw {
+		reqURL += "&nsfw=true"
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var rankings MangaRankingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rankings); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &rankings, nil
+}
+
+func ValidateMangaRankingType(rankingType string) error {
+	validTypes := map[string]bool{
+		"all":          true,
+		"manga":        true,
+		"novels":       true,
+		"oneshots":     true,
+		"doujin":       true,
+		"manhwa":       true,
+		"manhua":       true,
+		"bypopularity": true,
+		"favorite":     true,
+	}
+	if !validTypes[rankingType] {
+		return fmt.Errorf("invalid ranking type: %s", rankingType)
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package mal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// MangaListStatus is a user's progress and rating for one manga
+type MangaListStatus struct {
+	Status          string   `json:"status"`
+	Score           int      `json:"score"`
+	NumVolumesRead  int      `json:"num_volumes_read"`
+	NumChaptersRead int      `json:"num_chapters_read"`
+	IsRereading     bool     `json:"is_rereading"`
+	StartDate       string   `json:"start_date,omitempty"`
+	FinishDate      string   `json:"finish_date,omitempty"`
+	Priority        int      `json:"priority,omitempty"`
+	NumTimesReread  int      `json:"num_times_reread,omitempty"`
+	RereadValue     int      `json:"reread_value,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	Comments        string   `json:"comments,omitempty"`
+	UpdatedAt       string   `json:"updated_at,omitempty"`
+}
+
+// MangaListEntry is one manga on a user's list
+type MangaListEntry struct {
+	Node       MangaDetails    `json:"node"`
+	ListStatus MangaListStatus `json:"list_status"`
+}
+
+type MangaListResponse struct {
+	Data   []MangaListEntry `json:"data"`
+	Paging Paging           `json:"paging"`
+}
+
+// MangaListStatusFields selects every list status field, including tags
+// and comments, for MangaListOptions.Fields
+const MangaListStatusFields = "list_status{status,score,num_volumes_read,num_chapters_read,is_rereading,start_date,finish_date,priority,num_times_reread,reread_value,tags,comments,updated_at}"
+
+// MangaListOptions filters and orders a user's manga list. Zero values are
+// left to MAL's defaults.
+type MangaListOptions struct {
+	Status string
+	Sort   string
+	Limit  int
+	Offset int
+	// Fields are manga fields to request in addition to the list status,
+	// volume and chapter counts and alternative titles. A list_status{...}
+	// selection replaces the default list status fields.
+	Fields []string
+}
+
+// MangaListUpdate holds the fields to change on a manga list entry. Nil
+// fields are left untouched.
+type MangaListUpdate struct {
+	Status          *string
+	Score           *int
+	NumVolumesRead  *int
+	NumChaptersRead *int
+	IsRereading     *bool
+	NumTimesReread  *int
+	Priority        *int
+	StartDate       *string
+	FinishDate      *string
+	Tags            *[]string
+	Comments        *string
+}
+
+// Validate checks the update against MAL's accepted values
+func (u MangaListUpdate) Validate() error {
+	if u.Status != nil {
+		if err := ValidateMangaListStatus(*u.Status); err != nil {
+			return err
+		}
+	}
+	if u.Score != nil {
+		if err := ValidateScore(*u.Score); err != nil {
+			return err
+		}
+	}
+	if u.NumVolumesRead != nil && *u.NumVolumesRead < 0 {
+		return fmt.Errorf("volumes read cannot be negative, got %d", *u.NumVolumesRead)
+	}
+	if u.NumChaptersRead != nil && *u.NumChaptersRead < 0 {
+		return fmt.Errorf("chapters read cannot be negative, got %d", *u.NumChaptersRead)
+	}
+	if u.NumTimesReread != nil && *u.NumTimesReread < 0 {
+		return fmt.Errorf("times reread cannot be negative, got %d", *u.NumTimesReread)
+	}
+	if u.Priority != nil && (*u.Priority < 0 || *u.Priority > 2) {
+		return fmt.Errorf("priority must be between 0 and 2, got %d", *u.Priority)
+	}
+	return nil
+}
+
+func (u MangaListUpdate) values() url.Values {
+	form := url.Values{}
+	if u.Status != nil {
+		form.Set("status", *u.Status)
+	}
+	if u.Score != nil {
+		form.Set("score", strconv.Itoa(*u.Score))
+	}
+	if u.NumVolumesRead != nil {
+		form.Set("num_volumes_read", strconv.Itoa(*u.NumVolumesRead))
+	}
+	if u.NumChaptersRead != nil {
+		form.Set("num_chapters_read", strconv.Itoa(*u.NumChaptersRead))
+	}
+	if u.IsRereading != nil {
+		form.Set("is_rereading", strconv.FormatBool(*u.IsRereading))
+	}
+	if u.NumTimesReread != nil {
+		form.Set("num_times_reread", strconv.Itoa(*u.NumTimesReread))
+	}
+	if u.Priority != nil {
+		form.Set("priority", strconv.Itoa(*u.Priority))
+	}
+	if u.StartDate != nil {
+		form.Set("start_date", *u.StartDate)
+	}
+	if u.FinishDate != nil {
+		form.Set("finish_date", *u.FinishDate)
+	}
+	if u.Tags != nil {
+		form.Set("tags", strings.Join(*u.Tags, ","))
+	}
+	if u.Comments != nil {
+		form.Set("comments", *u.Comments)
+	}
+	return form
+}
+
+// MangaList returns a user's manga list. Use "@me" for the logged-in user.
+func (l *UserListService) MangaList(userName string, opts MangaListOptions) (*MangaListResponse, error) {
+	return l.MangaListContext(context.Background(), userName, opts)
+}
+
+// MangaListContext is like MangaList but uses ctx for the request
+func (l *UserListService) MangaListContext(ctx context.Context, userName string, opts MangaListOptions) (*MangaListResponse, error) {
+	params := url.Values{}
+	params.Add("fields", listFields([]string{"list_status", "num_volumes", "num_chapters", "alternative_titles"}, opts.Fields))
+	if opts.Status != "" {
+		params.Add("status", opts.Status)
+	}
+	if opts.Sort != "" {
+		params.Add("sort", opts.Sort)
+	}
+	if opts.Limit > 0 {
+		params.Add("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Offset > 0 {
+		params.Add("offset", strconv.Itoa(opts.Offset))
+	}
+	if l.client.nsfw {
+		params.Add("nsfw", "true")
+	}
+
+	reqURL := l.client.baseURL.String() + "users/" + url.PathEscape(userName) + "/mangalist?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var list MangaListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &list, nil
+}
+
+// UpdateManga adds a manga to the logged-in user's list or changes its
+// entry, returning the entry as stored by MAL
+func (l *UserListService) UpdateManga(mangaID int, update MangaListUpdate) (*MangaListStatus, error) {
+	return l.UpdateMangaContext(context.Background(), mangaID, update)
+}
+
+// UpdateMangaContext is like UpdateManga but uses ctx for the request
+func (l *UserListService) UpdateMangaContext(ctx context.Context, mangaID int, update MangaListUpdate) (*MangaListStatus, error) {
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+
+	reqURL := l.client.baseURL.String() + "manga/" + strconv.Itoa(mangaID) + "/my_list_status"
+
+	req, err := http.NewRequestWithContext(ctx, "PATCH", reqURL, strings.NewReader(update.values().Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var status MangaListStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &status, nil
+}
+
+// listFields joins the fields for a list request. A list_status{...}
+// sub-field selection in extra replaces the plain list_status default.
+func listFields(defaults, extra []string) string {
+	fields := make([]string, 0, len(defaults)+len(extra))
+	for _, f := range defaults {
+		if f == "list_status" && hasListStatusSelection(extra) {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	return strings.Join(append(fields, extra...), ",")
+}
+
+func hasListStatusSelection(fields []string) bool {
+	for _, f := range fields {
+		if strings.HasPrefix(f, "list_status{") {
+			return true
+		}
+	}
+	return false
+}
+
+func ValidateMangaListStatus(status string) error {
+	validStatuses := map[string]bool{
+		"reading":      true,
+		"completed":    true,
+		"on_hold":      true,
+		"dropped":      true,
+		"plan_to_read": true,
+	}
+	if !validStatuses[status] {
+		return fmt.Errorf("invalid list status: %s", status)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package mal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+)
+
+// paginate yields the items of a list endpoint page by page, following
+// paging.next until it runs out or maxResults items have been yielded (0
+// means no limit). Pages are only fetched as the caller consumes items, so
+// results can be shown while later pages are still to come. An error ends
+// the sequence after being yielded once.
+func paginate[R any, T any](ctx context.Context, c *Client, first func(context.Context) (*R, error), items func(*R) ([]T, string), maxResults int) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		page, err := first(ctx)
+		yielded := 0
+		for {
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			data, next := items(page)
+			for _, item := range data {
+				if !yield(item, nil) {
+					return
+				}
+				yielded++
+				if maxResults > 0 && yielded >= maxResults {
+					return
+				}
+			}
+
+			if next == "" || len(data) == 0 {
+				return
+			}
+			page, err = getPage[R](ctx, c, next)
+		}
+	}
+}
+
+// firstPageSize avoids fetching more than maxResults items in the first
+// request
+func firstPageSize(pageSize, maxResults int) int {
+	if maxResults > 0 && maxResults < pageSize {
+		return maxResults
+	}
+	return pageSize
+}
+
+// getPage fetches and decodes a paging.next URL
+func getPage[R any](ctx context.Context, c *Client, pageURL string) (*R, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var page R
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	c.recordAnime(ctx, &page)
+	return &page, nil
+}
+
+// SearchAll is like Search but follows pagination, yielding up to
+// maxResults results (0 for all of them) while requesting pageSize results
+// at a time
+func (a *AnimeService) SearchAll(query string, pageSize, maxResults int, fields ...string) iter.Seq2[AnimeData, error] {
+	return a.SearchAllContext(context.Background(), query, pageSize, maxResults, fields...)
+}
+
+// SearchAllContext is like SearchAll but uses ctx for its requests
+func (a *AnimeService) SearchAllContext(ctx context.Context, query string, pageSize, maxResults int, fields ...string) iter.Seq2[AnimeData, error] {
+	return paginate(ctx, a.client,
+		func(ctx context.Context) (*AnimeSearchResponse, error) {
+			return a.SearchContext(ctx, query, firstPageSize(pageSize, maxResults), fields...)
+		},
+		func(r *AnimeSearchResponse) ([]AnimeData, string) { return r.Data, r.Paging.Next },
+		maxResults)
+}
+
+// RankingsAll is like Rankings but follows pagination from offset, yielding
+// up to maxResults entries (0 for all of them) while requesting pageSize
+// entries at a time
+func (a *AnimeService) RankingsAll(rankingType string, pageSize, offset, maxResults int, fields ...string) iter.Seq2[AnimeRankingData, error] {
+	return a.RankingsAllContext(context.Background(), rankingType, pageSize, offset, maxResults, fields...)
+}
+
+// RankingsAllContext is like RankingsAll but uses ctx for its requests
+func (a *AnimeService) RankingsAllContext(ctx context.Context, rankingType string, pageSize, offset, maxResults int, fields ...string) iter.Seq2[AnimeRankingData, error] {
+	return paginate(ctx, a.client,
+		func(ctx context.Context) (*AnimeRankingResponse, error) {
+			return a.RankingsContext(ctx, rankingType, firstPageSize(pageSize, maxResults), offset, fields...)
+		},
+		func(r *AnimeRankingResponse) ([]AnimeRankingData, string) { return r.Data, r.Paging.Next },
+		maxResults)
+}
+
+// SeasonalAll is like Seasonal but follows pagination, yielding up to
+// maxResults anime (0 for all of them) while requesting pageSize anime at a
+// time
+func (a *AnimeService) SeasonalAll(year int, season, sort string, pageSize, maxResults int, fields ...string) iter.Seq2[AnimeData, error] {
+	return a.SeasonalAllContext(context.Background(), year, season, sort, pageSize, maxResults, fields...)
+}
+
+// SeasonalAllContext is like SeasonalAll but uses ctx for its requests
+func (a *AnimeService) SeasonalAllContext(ctx context.Context, year int, season, sort string, pageSize, maxResults int, fields ...string) iter.Seq2[AnimeData, error] {
+	return paginate(ctx, a.client,
+		func(ctx context.Context) (*AnimeSeasonResponse, error) {
+			return a.SeasonalContext(ctx, year, season, sort, firstPageSize(pageSize, maxResults), 0, fields...)
+		},
+		func(r *AnimeSeasonResponse) ([]AnimeData, string) { return r.Data, r.Paging.Next },
+		maxResults)
+}
+
+// AnimeListAll is like AnimeList but follows pagination, yielding up to
+// maxResults entries (0 for the whole list). opts.Limit sets the page size
+// and opts.Offset where to start.
+func (l *UserListService) AnimeListAll(userName string, opts AnimeListOptions, maxResults int) iter.Seq2[AnimeListEntry, error] {
+	return l.AnimeListAllContext(context.Background(), userName, opts, maxResults)
+}
+
+// AnimeListAllContext is like AnimeListAll but uses ctx for its requests
+func (l *UserListService) AnimeListAllContext(ctx context.Context, userName string, opts AnimeListOptions, maxResults int) iter.Seq2[AnimeListEntry, error] {
+	return paginate(ctx, l.client,
+		func(ctx context.Context) (*AnimeListResponse, error) {
+			return l.AnimeListContext(ctx, userName, opts)
+		},
+		func(r *AnimeListResponse) ([]AnimeListEntry, string) { return r.Data, r.Paging.Next },
+		maxResults)
+}
+
+// MangaListAll is like MangaList but follows pagination, yielding up to
+// maxResults entries (0 for the whole list). opts.Limit sets the page size
+// and opts.Offset where to start.
+func (l *UserListService) MangaListAll(userName string, opts MangaListOptions, maxResults int) iter.Seq2[MangaListEntry, error] {
+	return l.MangaListAllContext(context.Background(), userName, opts, maxResults)
+}
+
+// MangaListAllContext is like MangaListAll but uses ctx for its requests
+func (l *UserListService) MangaListAllContext(ctx context.Context, userName string, opts MangaListOptions, maxResults int) iter.Seq2[MangaListEntry, error] {
+	return paginate(ctx, l.client,
+		func(ctx context.Context) (*MangaListResponse, error) {
+			return l.MangaListContext(ctx, userName, opts)
+		},
+		func(r *MangaListResponse) ([]MangaListEntry, string) { return r.Data, r.Paging.Next },
+		maxResults)
+}
@@ -0,0 +1,182 @@
+package mal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// pagedServer serves a search over count anime, following limit and
+// offset and linking each page to the next through paging.next
+type pagedServer struct {
+	*httptest.Server
+	count int
+	// failAt makes the request for this offset fail, if set
+	failAt int
+
+	mu       sync.Mutex
+	requests []string
+}
+
+func newPagedServer(t *testing.T, count int) *pagedServer {
+	t.Helper()
+	s := &pagedServer{count: count, failAt: -1}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *pagedServer) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.URL.RawQuery)
+	s.mu.Unlock()
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	if offset == s.failAt {
+		http.Error(w, `{"error":"internal"}`, http.StatusInternalServerError)
+		return
+	}
+	page := AnimeSearchResponse{Data: []AnimeData{}}
+	for id := offset + 1; id <= min(offset+limit, s.count); id++ {
+		page.Data = append(page.Data, AnimeData{Node: AnimeDetails{ID: id}})
+	}
+	if offset+limit < s.count {
+		page.Paging.Next = fmt.Sprintf("%s%s?limit=%d&offset=%d", s.URL, r.URL.Path, limit, offset+limit)
+	}
+	json.NewEncoder(w).Encode(page)
+}
+
+func (s *pagedServer) client(t *testing.T) *Client {
+	t.Helper()
+	client := NewClient(s.Client(), "client-id")
+	if err := client.SetBaseURL(s.URL); err != nil {
+		t.Fatal(err)
+	}
+	client.SetRetryPolicy(RetryPolicy{})
+	return client
+}
+
+func (s *pagedServer) numRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+func TestSearchAllFollowsPaging(t *testing.T) {
+	tests := []struct {
+		name         string
+		count        int
+		pageSize     int
+		maxResults   int
+		wantIDs      int
+		wantRequests int
+	}{
+		{"every page", 25, 10, 0, 25, 3},
+		{"exact pages", 20, 10, 0, 20, 2},
+		{"cutoff across pages", 100, 10, 25, 25, 3},
+		{"cutoff on a page boundary", 100, 10, 20, 20, 2},
+		{"cutoff within the first page", 100, 10, 5, 5, 1},
+		{"cutoff past the end", 15, 10, 50, 15, 2},
+		{"empty result", 0, 10, 0, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newPagedServer(t, tt.count)
+			client := server.client(t)
+
+			var ids []int
+			for anime, err := range client.Anime.SearchAllContext(context.Background(), "query", tt.pageSize, tt.maxResults) {
+				if err != nil {
+					t.Fatalf("SearchAllContext: %v", err)
+				}
+				ids = append(ids, anime.Node.ID)
+			}
+			if len(ids) != tt.wantIDs {
+				t.Errorf("got %d anime, want %d", len(ids), tt.wantIDs)
+			}
+			for i, id := range ids {
+				if id != i+1 {
+					t.Errorf("anime %d has ID %d, want %d", i, id, i+1)
+					break
+				}
+			}
+			if got := server.numRequests(); got != tt.wantRequests {
+				t.Errorf("made %d requests, want %d: %q", got, tt.wantRequests, server.requests)
+			}
+		})
+	}
+}
+
+func TestSearchAllFirstPageSize(t *testing.T) {
+	server := newPagedServer(t, 100)
+	client := server.client(t)
+
+	for _, err := range client.Anime.SearchAllContext(context.Background(), "query", 50, 5) {
+		if err != nil {
+			t.Fatalf("SearchAllContext: %v", err)
+		}
+	}
+	if len(server.requests) != 1 {
+		t.Fatalf("made %d requests, want 1", len(server.requests))
+	}
+	if got := server.requests[0]; !containsParam(got, "limit", "5") {
+		t.Errorf("first request %q does not ask for 5 results", got)
+	}
+}
+
+func TestSearchAllStopsEarly(t *testing.T) {
+	server := newPagedServer(t, 100)
+	client := server.client(t)
+
+	seen := 0
+	for _, err := range client.Anime.SearchAllContext(context.Background(), "query", 10, 0) {
+		if err != nil {
+			t.Fatalf("SearchAllContext: %v", err)
+		}
+		seen++
+		if seen == 15 {
+			break
+		}
+	}
+	// Breaking out during the second page fetches no third one
+	if got := server.numRequests(); got != 2 {
+		t.Errorf("made %d requests, want 2", got)
+	}
+}
+
+func TestSearchAllYieldsErrorOnce(t *testing.T) {
+	server := newPagedServer(t, 100)
+	server.failAt = 10
+	client := server.client(t)
+
+	var ids []int
+	var errs []error
+	for anime, err := range client.Anime.SearchAllContext(context.Background(), "query", 10, 0) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, anime.Node.ID)
+	}
+	if len(ids) != 10 {
+		t.Errorf("got %d anime before the error, want 10", len(ids))
+	}
+	if len(errs) != 1 {
+		t.Fatalf("got %d errors, want 1", len(errs))
+	}
+	if !hasStatus(errs[0], http.StatusInternalServerError) {
+		t.Errorf("error = %v, want the server error", errs[0])
+	}
+}
+
+func containsParam(rawQuery, name, value string) bool {
+	params, err := url.ParseQuery(rawQuery)
+	return err == nil && params.Get(name) == value
+}
@@ -0,0 +1,271 @@
+package mal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+const (
+	// DefaultRecommendSeeds is how many of the user's top-scored completed
+	// anime recommendations are drawn from
+	DefaultRecommendSeeds = 10
+	// DefaultRecommendLimit is how many recommendations are returned
+	DefaultRecommendLimit = 10
+
+	// suggestionWeight is what appearing in MAL's suggestions adds to an
+	// anime's weight, about the same as a 7/10 anime recommending it a few
+	// times
+	suggestionWeight = 1.0
+
+	// recommendationFields are requested for each recommended anime
+	recommendationFields = "alternative_titles,mean,media_type,num_episodes,start_season"
+)
+
+// RecommendOptions controls how Recommend picks anime
+type RecommendOptions struct {
+	// User whose list recommendations are drawn from; "" means the
+	// logged-in user
+	User string
+	// Seeds is how many top-scored completed anime to draw from;
+	// 0 means DefaultRecommendSeeds
+	Seeds int
+	// Limit is how many recommendations to return; 0 means
+	// DefaultRecommendLimit
+	Limit int
+	// NoSuggestions skips MAL's personalized suggestions, which are only
+	// available for the logged-in user
+	NoSuggestions bool
+}
+
+// RecommendationReason is a scored anime on the user's list that
+// recommends another
+type RecommendationReason struct {
+	Anime     AnimeNode `json:"anime"`
+	UserScore int       `json:"user_score"`
+	// NumRecommendations is how many MAL users recommend the other anime
+	// to fans of this one
+	NumRecommendations int `json:"num_recommendations"`
+}
+
+// Recommendation is an anime suggested from the user's list
+type Recommendation struct {
+	Anime AnimeDetails `json:"anime"`
+	// Weight orders recommendations; it only means something relative to
+	// the other recommendations in the same result
+	Weight  float64                `json:"weight"`
+	Reasons []RecommendationReason `json:"reasons"`
+	// SuggestedByMAL is set when the anime is also in MAL's suggestions
+	SuggestedByMAL bool `json:"suggested_by_mal"`
+}
+
+// RecommendResult holds recommendations and the anime they came from
+type RecommendResult struct {
+	// Seeds are the completed anime recommendations were drawn from
+	Seeds           []AnimeListEntry `json:"seeds"`
+	Recommendations []Recommendation `json:"recommendations"`
+	// Errors lists anime whose recommendations could not be fetched
+	Errors []BatchError `json:"errors,omitempty"`
+	// SuggestionsErr is why MAL's suggestions were left out, if they were
+	// asked for but could not be fetched
+	SuggestionsErr error `json:"-"`
+}
+
+// Suggestions returns MAL's personalized suggestions for the logged-in user
+func (a *AnimeService) Suggestions(limit, offset int, fields ...string) (*AnimeSearchResponse, error) {
+	return a.SuggestionsContext(context.Background(), limit, offset, fields...)
+}
+
+// SuggestionsContext is like Suggestions but uses ctx for the request
+func (a *AnimeService) SuggestionsContext(ctx context.Context, limit, offset int, fields ...string) (*AnimeSearchResponse, error) {
+	params := url.Values{}
+	params.Add("limit", strconv.Itoa(limit))
+	params.Add("offset", strconv.Itoa(offset))
+	params.Add("fields", fieldsParam(fields, DefaultAnimeListFields))
+	if a.client.nsfw {
+		params.Add("nsfw", "true")
+	}
+
+	reqURL := a.client.baseURL.String() + "anime/suggestions?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var suggestions AnimeSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&suggestions); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	a.client.recordAnime(ctx, &suggestions)
+
+	return &suggestions, nil
+}
+
+// Recommend suggests anime from the recommendations of the user's
+// highest-scored completed anime. Each recommendation adds
+//
+//	user score / 10 × ln(1 + number of MAL users recommending it)
+//
+// to the recommended anime's weight, so anime recommended from several
+// favorites, or by many users, come first. Anime in MAL's own suggestions
+// get a bonus. Anything already on the user's list is left out.
+func (l *UserListService) Recommend(opts RecommendOptions) (*RecommendResult, error) {
+	return l.RecommendContext(context.Background(), opts)
+}
+
+// RecommendContext is like Recommend but uses ctx for its requests
+func (l *UserListService) RecommendContext(ctx context.Context, opts RecommendOptions) (*RecommendResult, error) {
+	if opts.User == "" {
+		opts.User = "@me"
+	}
+	if opts.Seeds <= 0 {
+		opts.Seeds = DefaultRecommendSeeds
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultRecommendLimit
+	}
+
+	// The whole list is needed to leave out anime the user already has
+	listed := make(map[int]bool)
+	completed := []AnimeListEntry{}
+	for entry, err := range l.AnimeListAllContext(ctx, opts.User, AnimeListOptions{Limit: 1000}, 0) {
+		if err != nil {
+			return nil, err
+		}
+		listed[entry.Node.ID] = true
+		if entry.ListStatus.Status == "completed" && entry.ListStatus.Score > 0 {
+			completed = append(completed, entry)
+		}
+	}
+
+	// Highest scores first, most recently updated among equal scores
+	sort.SliceStable(completed, func(i, j int) bool {
+		a, b := completed[i].ListStatus, completed[j].ListStatus
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.UpdatedAt > b.UpdatedAt
+	})
+	result := &RecommendResult{Seeds: completed[:min(len(completed), opts.Seeds)]}
+	if len(result.Seeds) == 0 {
+		result.Recommendations = []Recommendation{}
+		return result, nil
+	}
+
+	seedIDs := make([]int, len(result.Seeds))
+	scores := make(map[int]int, len(result.Seeds))
+	for i, seed := range result.Seeds {
+		seedIDs[i] = seed.Node.ID
+		scores[seed.Node.ID] = seed.ListStatus.Score
+	}
+	batch, err := l.client.Anime.BatchDetailsContext(ctx, seedIDs, "alternative_titles,recommendations")
+	if err != nil {
+		return nil, err
+	}
+	result.Errors = batch.Errors
+
+	candidates := make(map[int]*Recommendation)
+	candidate := func(anime AnimeNode) *Recommendation {
+		rec, ok := candidates[anime.ID]
+		if !ok {
+			rec = &Recommendation{
+				Anime:   AnimeDetails{ID: anime.ID, Title: anime.Title, MainPicture: anime.MainPicture},
+				Reasons: []RecommendationReason{},
+			}
+			candidates[anime.ID] = rec
+		}
+		return rec
+	}
+
+	for _, seed := range batch.Results {
+		score := scores[seed.ID]
+		source := AnimeNode{ID: seed.ID, Title: seed.Title, MainPicture: seed.MainPicture, AlternativeTitles: seed.AlternativeTitles}
+		for _, r := range seed.Recommendations {
+			if listed[r.Node.ID] {
+				continue
+			}
+			rec := candidate(r.Node)
+			rec.Weight += float64(score) / 10 * math.Log1p(float64(r.NumRecommendations))
+			rec.Reasons = append(rec.Reasons, RecommendationReason{
+				Anime:              source,
+				UserScore:          score,
+				NumRecommendations: r.NumRecommendations,
+			})
+		}
+	}
+
+	if !opts.NoSuggestions && opts.User == "@me" {
+		// The suggestions endpoint is flaky; recommendations from the
+		// list are still worth returning without it
+		suggestions, err := l.client.Anime.SuggestionsContext(ctx, 100, 0, "id", "title")
+		if err != nil && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			result.SuggestionsErr = err
+			suggestions = &AnimeSearchResponse{}
+		}
+		for _, s := range suggestions.Data {
+			if listed[s.Node.ID] {
+				continue
+			}
+			rec := candidate(AnimeNode{ID: s.Node.ID, Title: s.Node.Title, MainPicture: s.Node.MainPicture})
+			rec.Weight += suggestionWeight
+			rec.SuggestedByMAL = true
+		}
+	}
+
+	recommendations := make([]Recommendation, 0, len(candidates))
+	for _, rec := range candidates {
+		sort.SliceStable(rec.Reasons, func(i, j int) bool {
+			return rec.Reasons[i].UserScore > rec.Reasons[j].UserScore
+		})
+		recommendations = append(recommendations, *rec)
+	}
+	sort.Slice(recommendations, func(i, j int) bool {
+		a, b := recommendations[i], recommendations[j]
+		if a.Weight != b.Weight {
+			return a.Weight > b.Weight
+		}
+		return a.Anime.ID < b.Anime.ID
+	})
+	recommendations = recommendations[:min(len(recommendations), opts.Limit)]
+
+	// Fill in titles, scores and episode counts for the ones kept
+	ids := make([]int, len(recommendations))
+	for i, rec := range recommendations {
+		ids[i] = rec.Anime.ID
+	}
+	details, err := l.client.Anime.BatchDetailsContext(ctx, ids, recommendationFields)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]AnimeDetails, len(details.Results))
+	for _, d := range details.Results {
+		byID[d.ID] = d
+	}
+	for i := range recommendations {
+		if d, ok := byID[recommendations[i].Anime.ID]; ok {
+			recommendations[i].Anime = d
+		}
+	}
+
+	result.Recommendations = recommendations
+	return result, nil
+}
@@ -0,0 +1,41 @@
+package mal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecommendWithoutSuggestions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/@me/animelist":
+			w.Write([]byte(`{"data":[{"node":{"id":1,"title":"Cowboy Bebop"},"list_status":{"status":"completed","score":10}}],"paging":{}}`))
+		case "/anime/1":
+			w.Write([]byte(`{"id":1,"title":"Cowboy Bebop","recommendations":[{"node":{"id":2,"title":"Samurai Champloo"},"num_recommendations":40}]}`))
+		case "/anime/2":
+			w.Write([]byte(`{"id":2,"title":"Samurai Champloo","mean":8.5}`))
+		case "/anime/suggestions":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client(), "client-id")
+	client.SetBaseURL(server.URL)
+	client.SetRetryPolicy(RetryPolicy{})
+
+	result, err := client.List.RecommendContext(context.Background(), RecommendOptions{})
+	if err != nil {
+		t.Fatalf("RecommendContext failed with the suggestions endpoint down: %v", err)
+	}
+	if len(result.Recommendations) != 1 || result.Recommendations[0].Anime.ID != 2 {
+		t.Errorf("recommendations = %+v, want Samurai Champloo", result.Recommendations)
+	}
+	if !hasStatus(result.SuggestionsErr, http.StatusInternalServerError) {
+		t.Errorf("SuggestionsErr = %v, want the 500", result.SuggestionsErr)
+	}
+}
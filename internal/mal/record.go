@@ -0,0 +1,49 @@
+package mal
+
+import "context"
+
+// AnimeRecorder is given the anime a Client fetches, for example to keep a
+// local index of every anime that has been looked up
+type AnimeRecorder interface {
+	RecordAnime(ctx context.Context, anime []AnimeDetails) error
+}
+
+// SetAnimeRecorder passes the anime in search, detail, ranking, seasonal,
+// suggestion and list responses to r, including responses served from the
+// cache. Pass nil to stop recording.
+func (c *Client) SetAnimeRecorder(r AnimeRecorder) {
+	c.recorder = r
+}
+
+// recordAnime passes the anime in a decoded response to the recorder
+func (c *Client) recordAnime(ctx context.Context, response any) {
+	if c.recorder == nil {
+		return
+	}
+	var anime []AnimeDetails
+	switch r := response.(type) {
+	case *AnimeDetails:
+		anime = []AnimeDetails{*r}
+	case *AnimeSearchResponse:
+		for _, d := range r.Data {
+			anime = append(anime, d.Node)
+		}
+	case *AnimeSeasonResponse:
+		for _, d := range r.Data {
+			anime = append(anime, d.Node)
+		}
+	case *AnimeRankingResponse:
+		for _, d := range r.Data {
+			anime = append(anime, d.Node)
+		}
+	case *AnimeListResponse:
+		for _, d := range r.Data {
+			anime = append(anime, d.Node)
+		}
+	}
+	if len(anime) == 0 {
+		return
+	}
+	// Like a cache write, a failed record does not fail the request
+	c.recorder.RecordAnime(ctx, anime)
+}
@@ -0,0 +1,81 @@
+package mal
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// idRecorder records the IDs of the anime it is given
+type idRecorder struct {
+	mu  sync.Mutex
+	ids []int
+}
+
+func (r *idRecorder) RecordAnime(ctx context.Context, anime []AnimeDetails) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, a := range anime {
+		r.ids = append(r.ids, a.ID)
+	}
+	return nil
+}
+
+func (r *idRecorder) take() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := r.ids
+	r.ids = nil
+	return ids
+}
+
+func TestRecordAnime(t *testing.T) {
+	ctx := context.Background()
+	server := newPagedServer(t, 25)
+	client := server.client(t)
+	recorder := &idRecorder{}
+	client.SetAnimeRecorder(recorder)
+
+	// Every page is recorded, not only the first
+	for _, err := range client.Anime.SearchAllContext(ctx, "query", 10, 0) {
+		if err != nil {
+			t.Fatalf("SearchAllContext: %v", err)
+		}
+	}
+	if got := recorder.take(); len(got) != 25 || !slices.IsSorted(got) || got[0] != 1 {
+		t.Errorf("recorded %v, want anime 1 to 25", got)
+	}
+
+	client.SetAnimeRecorder(nil)
+	if _, err := client.Anime.SearchContext(ctx, "query", 5); err != nil {
+		t.Fatal(err)
+	}
+	if got := recorder.take(); got != nil {
+		t.Errorf("recorded %v after recording was turned off", got)
+	}
+}
+
+func TestRecordAnimeDetailsFromCache(t *testing.T) {
+	ctx := context.Background()
+	g := newShowGraph(t)
+	client := g.client(t)
+	client.SetCache(&DiskCache{Dir: t.TempDir()}, CachePolicy{DetailsTTL: time.Hour})
+	recorder := &idRecorder{}
+	client.SetAnimeRecorder(recorder)
+
+	for range 2 {
+		if _, err := client.Anime.DetailsContext(ctx, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := recorder.take(); !slices.Equal(got, []int{2, 2}) {
+		t.Errorf("recorded %v, want anime 2 from the network and from the cache", got)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.fetched[2] != 1 {
+		t.Errorf("anime 2 fetched %d times, want once", g.fetched[2])
+	}
+}
@@ -0,0 +1,265 @@
+package mal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// ConfidentMatch is the confidence above which a candidate is taken
+	// as the answer without asking
+	ConfidentMatch = 0.9
+	// AmbiguityMargin is how close the runner-up can score before a
+	// confident match is treated as ambiguous
+	AmbiguityMargin = 0.05
+
+	// resolveFields are requested for each search candidate
+	resolveFields = "alternative_titles,media_type,start_season,num_episodes"
+)
+
+// AnimeCandidate is a possible match for a name
+type AnimeCandidate struct {
+	Anime AnimeDetails `json:"anime"`
+	// Confidence ranges from 0 to 1, where 1 is an exact title match
+	Confidence float64 `json:"confidence"`
+	// MatchedTitle is the title or synonym that best matched the name
+	MatchedTitle string `json:"matched_title"`
+}
+
+// AnimeResolution is the ranked result of resolving a name
+type AnimeResolution struct {
+	Query      string           `json:"query"`
+	Candidates []AnimeCandidate `json:"candidates"`
+}
+
+// Best returns the top candidate if it is confident and clearly ahead of
+// the runner-up
+func (r *AnimeResolution) Best() (*AnimeCandidate, bool) {
+	if len(r.Candidates) == 0 {
+		return nil, false
+	}
+	return &r.Candidates[0], isClearMatch(len(r.Candidates), func(i int) float64 { return r.Candidates[i].Confidence })
+}
+
+// MangaCandidate is a possible match for a manga name
+type MangaCandidate struct {
+	Manga MangaNode `json:"manga"`
+	// Confidence ranges from 0 to 1, where 1 is an exact title match
+	Confidence float64 `json:"confidence"`
+	// MatchedTitle is the title or synonym that best matched the name
+	MatchedTitle string `json:"matched_title"`
+}
+
+// MangaResolution is the ranked result of resolving a manga name
+type MangaResolution struct {
+	Query      string           `json:"query"`
+	Candidates []MangaCandidate `json:"candidates"`
+}
+
+// Best returns the top candidate like AnimeResolution.Best
+func (r *MangaResolution) Best() (*MangaCandidate, bool) {
+	if len(r.Candidates) == 0 {
+		return nil, false
+	}
+	return &r.Candidates[0], isClearMatch(len(r.Candidates), func(i int) float64 { return r.Candidates[i].Confidence })
+}
+
+// isClearMatch reports whether the first of n ranked candidates is
+// confident and clearly ahead of the runner-up
+func isClearMatch(n int, confidence func(i int) float64) bool {
+	if confidence(0) < ConfidentMatch {
+		return false
+	}
+	return n == 1 || confidence(0)-confidence(1) >= AmbiguityMargin
+}
+
+// Resolve searches for name and ranks the results by how well their
+// titles, English and Japanese titles, and synonyms match it. limit is the
+// number of search results to consider.
+func (a *AnimeService) Resolve(name string, limit int) (*AnimeResolution, error) {
+	return a.ResolveContext(context.Background(), name, limit)
+}
+
+// ResolveContext is like Resolve but uses ctx for the request
+func (a *AnimeService) ResolveContext(ctx context.Context, name string, limit int) (*AnimeResolution, error) {
+	return ResolveAnime(ctx, a, name, limit)
+}
+
+// ResolveAnime is like AnimeService.Resolve but searches src, e.g. the
+// local mirror
+func ResolveAnime(ctx context.Context, src AnimeSource, name string, limit int) (*AnimeResolution, error) {
+	query := normalizeTitle(name)
+	if query == "" {
+		return nil, fmt.Errorf("name cannot be empty")
+	}
+
+	results, err := src.SearchContext(ctx, name, limit, resolveFields)
+	if err != nil {
+		return nil, err
+	}
+
+	resolution := &AnimeResolution{Query: name}
+	for _, result := range results.Data {
+		candidate := AnimeCandidate{Anime: result.Node}
+		candidate.Confidence, candidate.MatchedTitle = bestTitleMatch(query, result.Node.Title, result.Node.AlternativeTitles)
+		resolution.Candidates = append(resolution.Candidates, candidate)
+	}
+
+	// Stable so MAL's own relevance order breaks ties
+	sort.SliceStable(resolution.Candidates, func(i, j int) bool {
+		return resolution.Candidates[i].Confidence > resolution.Candidates[j].Confidence
+	})
+	return resolution, nil
+}
+
+// ResolveManga is like ResolveAnime for manga
+func ResolveManga(ctx context.Context, src MangaSource, name string, limit int) (*MangaResolution, error) {
+	query := normalizeTitle(name)
+	if query == "" {
+		return nil, fmt.Errorf("name cannot be empty")
+	}
+
+	results, err := src.SearchContext(ctx, name, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resolution := &MangaResolution{Query: name}
+	for _, result := range results.Data {
+		candidate := MangaCandidate{Manga: result.Node}
+		candidate.Confidence, candidate.MatchedTitle = bestTitleMatch(query, result.Node.Title, result.Node.AlternativeTitles)
+		resolution.Candidates = append(resolution.Candidates, candidate)
+	}
+
+	sort.SliceStable(resolution.Candidates, func(i, j int) bool {
+		return resolution.Candidates[i].Confidence > resolution.Candidates[j].Confidence
+	})
+	return resolution, nil
+}
+
+// bestTitleMatch scores each of a title's names against a normalized
+// query, returning the best score and the name that got it
+func bestTitleMatch(query, title string, alt AlternativeTitles) (float64, string) {
+	var best float64
+	var matched string
+	for _, t := range candidateTitles(title, alt) {
+		if score := titleSimilarity(query, normalizeTitle(t)); score > best {
+			best, matched = score, t
+		}
+	}
+	return best, matched
+}
+
+func candidateTitles(title string, alt AlternativeTitles) []string {
+	titles := []string{title}
+	if alt.En != "" {
+		titles = append(titles, alt.En)
+	}
+	if alt.Ja != "" {
+		titles = append(titles, alt.Ja)
+	}
+	if alt.Synonyms != nil {
+		titles = append(titles, *alt.Synonyms...)
+	}
+	return titles
+}
+
+// normalizeTitle lowercases a title, strips diacritics and collapses
+// punctuation to single spaces, so "Shingeki no Kyojin: The Final Season"
+// and "shingeki no kyojin the final season" compare equal
+func normalizeTitle(title string) string {
+	stripped, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), title)
+	if err != nil {
+		stripped = title
+	}
+
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(stripped) {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			space = false
+		} else {
+			space = true
+		}
+	}
+	return b.String()
+}
+
+// titleSimilarity scores how well a normalized title matches a normalized
+// query, from 0 to 1. Exact matches score 1; titles that extend the query
+// (sequels, movies) score below the title itself, so "naruto" prefers
+// Naruto over Naruto: Shippuuden.
+func titleSimilarity(query, title string) float64 {
+	if query == "" || title == "" {
+		return 0
+	}
+	if query == title {
+		return 1
+	}
+
+	q, t := []rune(query), []rune(title)
+	score := 1 - float64(levenshtein(q, t))/float64(max(len(q), len(t)))
+	score = max(score, 0.95*tokenDice(query, title))
+
+	// A title that starts with or contains the whole query is a strong
+	// match, weaker the more the title adds
+	coverage := float64(len(q)) / float64(len(t))
+	switch {
+	case strings.HasPrefix(title, query+" "):
+		score = max(score, 0.6+0.3*coverage)
+	case strings.Contains(title, query):
+		score = max(score, 0.5+0.3*coverage)
+	}
+	return score
+}
+
+// tokenDice is the Dice coefficient of the words in a and b
+func tokenDice(a, b string) float64 {
+	wordsA, wordsB := strings.Fields(a), strings.Fields(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+	counts := make(map[string]int, len(wordsA))
+	for _, w := range wordsA {
+		counts[w]++
+	}
+	common := 0
+	for _, w := range wordsB {
+		if counts[w] > 0 {
+			counts[w]--
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(wordsA)+len(wordsB))
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
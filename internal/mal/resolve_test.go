@@ -0,0 +1,86 @@
+package mal
+
+import (
+	"context"
+	"testing"
+)
+
+// fakeAnimeSource answers searches with fixed results
+type fakeAnimeSource struct {
+	AnimeSource
+	results []AnimeData
+}
+
+func (f fakeAnimeSource) SearchContext(ctx context.Context, query string, limit int, fields ...string) (*AnimeSearchResponse, error) {
+	return &AnimeSearchResponse{Data: f.results}, nil
+}
+
+func TestResolveAnimePrefersExactTitle(t *testing.T) {
+	// MAL ranks the sequel first for "naruto"
+	src := fakeAnimeSource{results: []AnimeData{
+		{Node: AnimeDetails{ID: 1735, Title: "Naruto: Shippuuden", AlternativeTitles: AlternativeTitles{En: "Naruto Shippuden", Ja: "NARUTO -ナルト- 疾風伝"}}},
+		{Node: AnimeDetails{ID: 20, Title: "Naruto", AlternativeTitles: AlternativeTitles{En: "Naruto", Ja: "NARUTO -ナルト-"}}},
+		{Node: AnimeDetails{ID: 34566, Title: "Boruto: Naruto Next Generations", AlternativeTitles: AlternativeTitles{En: "Boruto: Naruto Next Generations"}}},
+		{Node: AnimeDetails{ID: 442, Title: "Naruto Movie 1: Dai Katsugeki!! Yuki Hime Shinobu Houjou Dattebayo!"}},
+	}}
+
+	for _, name := range []string{"naruto", "Naruto", "  NARUTO "} {
+		resolution, err := ResolveAnime(context.Background(), src, name, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		best, ok := resolution.Best()
+		if !ok || best.Anime.ID != 20 {
+			t.Errorf("%q resolved to %+v (clear match %v), want Naruto (ID 20)", name, best.Anime, ok)
+		}
+	}
+
+	resolution, err := ResolveAnime(context.Background(), src, "naruto shippuden", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if best, ok := resolution.Best(); !ok || best.Anime.ID != 1735 {
+		t.Errorf("%q resolved to %+v (clear match %v), want Naruto: Shippuuden (ID 1735)", "naruto shippuden", best.Anime, ok)
+	}
+}
+
+// fakeMangaSource answers searches with fixed results
+type fakeMangaSource struct {
+	MangaSource
+	results []MangaData
+}
+
+func (f fakeMangaSource) SearchContext(ctx context.Context, query string, limit int) (*MangaSearchResponse, error) {
+	return &MangaSearchResponse{Data: f.results}, nil
+}
+
+func TestResolveManga(t *testing.T) {
+	synonyms := []string{"Berserk: The Prototype"}
+	src := fakeMangaSource{results: []MangaData{
+		{Node: MangaNode{ID: 1, Title: "Berserk: Shinen no Kami"}},
+		{Node: MangaNode{ID: 2, Title: "Berserk", AlternativeTitles: AlternativeTitles{Synonyms: &synonyms}}},
+	}}
+
+	resolution, err := ResolveManga(context.Background(), src, "berserk", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	best, ok := resolution.Best()
+	if !ok || best.Manga.ID != 2 {
+		t.Fatalf("Best = %+v, %v; want a clear match for ID 2", best, ok)
+	}
+
+	// Two equally good matches are ambiguous
+	src.results = append(src.results, MangaData{Node: MangaNode{ID: 3, Title: "BERSERK"}})
+	resolution, err = ResolveManga(context.Background(), src, "berserk", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := resolution.Best(); ok {
+		t.Error("Best picked one of two exact matches")
+	}
+
+	if _, err := ResolveManga(context.Background(), src, "  ?! ", 10); err == nil {
+		t.Error("resolved an empty name")
+	}
+}
@@ -0,0 +1,195 @@
+package mal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyServer answers each request with the next scripted failure, then
+// successfully once the script runs out
+type flakyServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	failures []func(w http.ResponseWriter)
+	requests []time.Time
+}
+
+func newFlakyServer(t *testing.T, failures ...func(w http.ResponseWriter)) *flakyServer {
+	t.Helper()
+	s := &flakyServer{failures: failures}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, time.Now())
+		var fail func(w http.ResponseWriter)
+		if len(s.failures) > 0 {
+			fail, s.failures = s.failures[0], s.failures[1:]
+		}
+		s.mu.Unlock()
+
+		if fail != nil {
+			fail(w)
+			return
+		}
+		w.Write([]byte(`{"id":1,"title":"Cowboy Bebop"}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *flakyServer) attempts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+func status(code int, retryAfter string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		if retryAfter != "" {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+		w.WriteHeader(code)
+	}
+}
+
+// dropConnection closes the connection without a response
+func dropConnection(w http.ResponseWriter) {
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err == nil {
+		conn.Close()
+	}
+}
+
+func newRetryClient(t *testing.T, server *flakyServer, policy RetryPolicy) *Client {
+	t.Helper()
+	client := NewClient(server.Client(), "client-id")
+	if err := client.SetBaseURL(server.URL); err != nil {
+		t.Fatal(err)
+	}
+	client.SetRetryPolicy(policy)
+	return client
+}
+
+var fastRetries = RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+func TestRetryServerErrors(t *testing.T) {
+	server := newFlakyServer(t, status(http.StatusBadGateway, ""), status(http.StatusServiceUnavailable, ""))
+	client := newRetryClient(t, server, fastRetries)
+
+	anime, err := client.Anime.DetailsContext(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("DetailsContext: %v", err)
+	}
+	if anime.Title != "Cowboy Bebop" {
+		t.Errorf("title = %q", anime.Title)
+	}
+	if got := server.attempts(); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+}
+
+func TestRetryNetworkErrors(t *testing.T) {
+	server := newFlakyServer(t, dropConnection, dropConnection)
+	client := newRetryClient(t, server, fastRetries)
+
+	if _, err := client.Anime.DetailsContext(context.Background(), 1); err != nil {
+		t.Fatalf("DetailsContext: %v", err)
+	}
+	if got := server.attempts(); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	server := newFlakyServer(t, status(http.StatusTooManyRequests, "1"))
+	// MaxDelay is far below Retry-After, which must still be honored
+	client := newRetryClient(t, server, RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, MaxElapsed: 5 * time.Second})
+
+	if _, err := client.Anime.DetailsContext(context.Background(), 1); err != nil {
+		t.Fatalf("DetailsContext: %v", err)
+	}
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.requests) != 2 {
+		t.Fatalf("attempts = %d, want 2", len(server.requests))
+	}
+	if wait := server.requests[1].Sub(server.requests[0]); wait < time.Second {
+		t.Errorf("retried after %v, want at least the 1s Retry-After", wait)
+	}
+}
+
+func TestRetryAfterBeyondBudgetGivesUp(t *testing.T) {
+	server := newFlakyServer(t, status(http.StatusTooManyRequests, "60"))
+	client := newRetryClient(t, server, RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxElapsed: time.Second})
+
+	start := time.Now()
+	_, err := client.Anime.DetailsContext(context.Background(), 1)
+	if !IsRateLimited(err) {
+		t.Fatalf("error = %v, want a 429", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("gave up after %v, want no wait", elapsed)
+	}
+	if got := server.attempts(); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
+
+func TestRetryExhaustsMaxRetries(t *testing.T) {
+	fail := status(http.StatusInternalServerError, "")
+	server := newFlakyServer(t, fail, fail, fail, fail, fail)
+	client := newRetryClient(t, server, fastRetries)
+
+	_, err := client.Anime.DetailsContext(context.Background(), 1)
+	if !hasStatus(err, http.StatusInternalServerError) {
+		t.Fatalf("error = %v, want a 500", err)
+	}
+	if got := server.attempts(); got != 4 {
+		t.Errorf("attempts = %d, want 4", got)
+	}
+}
+
+func TestRetryExhaustsMaxElapsed(t *testing.T) {
+	fail := status(http.StatusServiceUnavailable, "1")
+	server := newFlakyServer(t, fail, fail, fail, fail, fail)
+	// Only one of the 1s waits fits in the 1.5s budget
+	client := newRetryClient(t, server, RetryPolicy{MaxRetries: 10, BaseDelay: time.Millisecond, MaxElapsed: 1500 * time.Millisecond})
+
+	_, err := client.Anime.DetailsContext(context.Background(), 1)
+	if !hasStatus(err, http.StatusServiceUnavailable) {
+		t.Fatalf("error = %v, want a 503", err)
+	}
+	if got := server.attempts(); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+}
+
+func TestRateLimitIsSharedAcrossServices(t *testing.T) {
+	server := newFlakyServer(t)
+	client := newRetryClient(t, server, RetryPolicy{})
+	client.SetRateLimit(20, 1)
+
+	ctx := context.Background()
+	for i := range 6 {
+		var err error
+		if i%2 == 0 {
+			_, err = client.Anime.DetailsContext(ctx, 1)
+		} else {
+			_, err = client.Manga.DetailsContext(ctx, 1)
+		}
+		if err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	// 20 requests per second is one every 50ms, whichever service sends it
+	if span := server.requests[len(server.requests)-1].Sub(server.requests[0]); span < 240*time.Millisecond {
+		t.Errorf("6 requests took %v, want at least 250ms at 20/s", span)
+	}
+}
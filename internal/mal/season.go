@@ -0,0 +1,148 @@
+package mal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// AnimeSeasonResponse is a page of anime airing in a season
+type AnimeSeasonResponse struct {
+	Data   []AnimeData `json:"data"`
+	Paging Paging      `json:"paging"`
+	Season Season      `json:"season"`
+}
+
+// AnimeMediaTypes are the media types MAL gives anime
+var AnimeMediaTypes = []string{"tv", "ova", "movie", "special", "tv_special", "ona", "music", "cm", "pv", "unknown"}
+
+// ValidateAnimeMediaTypes checks that every type is one of AnimeMediaTypes
+func ValidateAnimeMediaTypes(types []string) error {
+	for _, t := range types {
+		if !slices.Contains(AnimeMediaTypes, t) {
+			return fmt.Errorf("invalid media type: %s", t)
+		}
+	}
+	return nil
+}
+
+// seasonalTypePageSize is how many anime SeasonalOfTypesContext requests at
+// a time while looking for ones of the wanted types
+const seasonalTypePageSize = 100
+
+// Seasonal returns anime that started airing in the given season. sort may
+// be empty, "anime_score" or "anime_num_list_users". fields selects the data
+// returned for each anime (see AnimeFields).
+func (a *AnimeService) Seasonal(year int, season, sort string, limit, offset int, fields ...string) (*AnimeSeasonResponse, error) {
+	return a.SeasonalContext(context.Background(), year, season, sort, limit, offset, fields...)
+}
+
+// SeasonalContext is like Seasonal but uses ctx for the request
+func (a *AnimeService) SeasonalContext(ctx context.Context, year int, season, sort string, limit, offset int, fields ...string) (*AnimeSeasonResponse, error) {
+	params := url.Values{}
+	if sort != "" {
+		params.Add("sort", sort)
+	}
+	params.Add("limit", fmt.Sprintf("%d", limit))
+	params.Add("offset", fmt.Sprintf("%d", offset))
+	params.Add("fields", fieldsParam(fields, DefaultAnimeListFields))
+	if a.client.nsfw {
+		params.Add("nsfw", "true")
+	}
+
+	reqURL := a.client.baseURL.String() + fmt.Sprintf("anime/season/%d/%s?", year, season) + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var seasonal AnimeSeasonResponse
+	if err := json.NewDecoder(resp.Body).Decode(&seasonal); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	a.client.recordAnime(ctx, &seasonal)
+	return &seasonal, nil
+}
+
+// SeasonalOfTypesContext is like SeasonalContext but only returns anime
+// whose media_type is one of types, following pagination until limit of
+// them are found. offset skips that many matching anime. Without types it
+// is the same as SeasonalContext.
+func (a *AnimeService) SeasonalOfTypesContext(ctx context.Context, year int, season, sort string, limit, offset int, types []string, fields ...string) (*AnimeSeasonResponse, error) {
+	if len(types) == 0 {
+		return a.SeasonalContext(ctx, year, season, sort, limit, offset, fields...)
+	}
+	if len(fields) == 0 {
+		fields = []string{DefaultAnimeListFields}
+	}
+	fields = append(fields, "media_type")
+
+	seasonal := &AnimeSeasonResponse{
+		Data:   []AnimeData{},
+		Season: Season{Year: year, Season: season},
+	}
+	for anime, err := range a.SeasonalAllContext(ctx, year, season, sort, seasonalTypePageSize, 0, fields...) {
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(types, anime.Node.MediaType) {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		seasonal.Data = append(seasonal.Data, anime)
+		if len(seasonal.Data) >= limit {
+			break
+		}
+	}
+	return seasonal, nil
+}
+
+// CurrentSeason returns the anime season containing t: winter is
+// January-March, spring April-June, summer July-September and fall
+// October-December.
+func CurrentSeason(t time.Time) Season {
+	seasons := [...]string{"winter", "spring", "summer", "fall"}
+	return Season{Year: t.Year(), Season: seasons[(int(t.Month())-1)/3]}
+}
+
+func ValidateSeason(season string) error {
+	validSeasons := map[string]bool{
+		"winter": true,
+		"spring": true,
+		"summer": true,
+		"fall":   true,
+	}
+	if !validSeasons[season] {
+		return fmt.Errorf("invalid season: %s", season)
+	}
+	return nil
+}
+
+func ValidateSeasonalSort(sort string) error {
+	validSorts := map[string]bool{
+		"anime_score":          true,
+		"anime_num_list_users": true,
+	}
+	if !validSorts[sort] {
+		return fmt.Errorf("invalid seasonal sort: %s", sort)
+	}
+	return nil
+}
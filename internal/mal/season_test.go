@@ -0,0 +1,74 @@
+package mal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// newSeasonServer serves a season of count anime in pages, every third
+// one a movie and the rest tv
+func newSeasonServer(t *testing.T, count int) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		page := AnimeSeasonResponse{Data: []AnimeData{}}
+		for id := offset + 1; id <= min(offset+limit, count); id++ {
+			mediaType := "tv"
+			if id%3 == 0 {
+				mediaType = "movie"
+			}
+			page.Data = append(page.Data, AnimeData{Node: AnimeDetails{ID: id, MediaType: mediaType}})
+		}
+		if offset+limit < count {
+			page.Paging.Next = fmt.Sprintf("%s%s?limit=%d&offset=%d", server.URL, r.URL.Path, limit, offset+limit)
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSeasonalOfTypesPagesUntilLimit(t *testing.T) {
+	server := newSeasonServer(t, 250)
+	client := NewClient(server.Client(), "client-id")
+	client.SetBaseURL(server.URL)
+
+	seasonal, err := client.Anime.SeasonalOfTypesContext(context.Background(), 2025, "fall", "", 10, 2, []string{"movie"})
+	if err != nil {
+		t.Fatalf("SeasonalOfTypesContext: %v", err)
+	}
+	if len(seasonal.Data) != 10 {
+		t.Fatalf("got %d anime, want 10", len(seasonal.Data))
+	}
+	// The first two movies (3 and 6) are skipped by the offset
+	for i, anime := range seasonal.Data {
+		if want := (i + 3) * 3; anime.Node.ID != want || anime.Node.MediaType != "movie" {
+			t.Errorf("anime %d = %d (%s), want movie %d", i, anime.Node.ID, anime.Node.MediaType, want)
+		}
+	}
+
+	// Asking for more than the season has returns what there is
+	seasonal, err = client.Anime.SeasonalOfTypesContext(context.Background(), 2025, "fall", "", 500, 0, []string{"movie"})
+	if err != nil {
+		t.Fatalf("SeasonalOfTypesContext: %v", err)
+	}
+	if len(seasonal.Data) != 83 {
+		t.Errorf("got %d anime, want all 83 movies", len(seasonal.Data))
+	}
+}
+
+func TestValidateAnimeMediaTypes(t *testing.T) {
+	if err := ValidateAnimeMediaTypes([]string{"tv", "movie", "ona"}); err != nil {
+		t.Errorf("valid types rejected: %v", err)
+	}
+	if err := ValidateAnimeMediaTypes([]string{"tv", "film"}); err == nil {
+		t.Error("invalid type accepted")
+	}
+}
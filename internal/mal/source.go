@@ -0,0 +1,31 @@
+package mal
+
+import "context"
+
+// AnimeSource looks up anime. AnimeService implements it against MAL;
+// other implementations, like the local mirror, let the same code read
+// from elsewhere.
+type AnimeSource interface {
+	SearchContext(ctx context.Context, query string, limit int, fields ...string) (*AnimeSearchResponse, error)
+	DetailsContext(ctx context.Context, animeID int, fields ...string) (*AnimeDetails, error)
+	BatchDetailsContext(ctx context.Context, animeIDs []int, fields ...string) (*BatchResult[AnimeDetails], error)
+}
+
+// MangaSource looks up manga, like AnimeSource does for anime
+type MangaSource interface {
+	SearchContext(ctx context.Context, query string, limit int) (*MangaSearchResponse, error)
+	DetailsContext(ctx context.Context, mangaID int) (*MangaDetails, error)
+	BatchDetailsContext(ctx context.Context, mangaIDs []int) (*BatchResult[MangaDetails], error)
+}
+
+// ListSource reads users' anime and manga lists
+type ListSource interface {
+	AnimeListContext(ctx context.Context, userName string, opts AnimeListOptions) (*AnimeListResponse, error)
+	MangaListContext(ctx context.Context, userName string, opts MangaListOptions) (*MangaListResponse, error)
+}
+
+var (
+	_ AnimeSource = (*AnimeService)(nil)
+	_ MangaSource = (*MangaService)(nil)
+	_ ListSource  = (*UserListService)(nil)
+)
@@ -0,0 +1,222 @@
+package mal
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// statsFields are requested for each list entry when computing stats
+var statsFields = []string{"genres", "studios", "average_episode_duration", "media_type", "mean"}
+
+// statsTopN is how many genres and studios ListStats keeps
+const statsTopN = 10
+
+// ListStats summarizes an anime list
+type ListStats struct {
+	Entries         int `json:"entries"`
+	EpisodesWatched int `json:"episodes_watched"`
+	// WatchMinutes is episodes watched times their average duration
+	WatchMinutes int `json:"watch_minutes"`
+	// CompletionRate is the share of started anime (everything but plan
+	// to watch) that was completed, from 0 to 1
+	CompletionRate float64 `json:"completion_rate"`
+
+	// MeanScore is the user's average score over scored entries
+	MeanScore float64 `json:"mean_score"`
+	// MeanDeviation is how much higher the user scores than the MAL
+	// community on average; negative when the user scores lower
+	MeanDeviation float64 `json:"mean_deviation"`
+	// MeanAbsoluteDeviation is the average distance between the user's
+	// scores and the community's, in either direction
+	MeanAbsoluteDeviation float64 `json:"mean_absolute_deviation"`
+	// ScoreDistribution counts entries scored 1 to 10, lowest first
+	ScoreDistribution []ScoreCount `json:"score_distribution"`
+
+	Statuses   []StatCount    `json:"statuses"`
+	MediaTypes []StatCount    `json:"media_types"`
+	TopGenres  []StatCount    `json:"top_genres"`
+	TopStudios []StatCount    `json:"top_studios"`
+	Years      []YearActivity `json:"years"`
+}
+
+// ScoreCount is the number of entries given a score
+type ScoreCount struct {
+	Score int `json:"score"`
+	Count int `json:"count"`
+}
+
+// StatCount is the number of entries with a status, genre, studio or
+// media type, and the user's mean score for them
+type StatCount struct {
+	Name      string  `json:"name"`
+	Count     int     `json:"count"`
+	MeanScore float64 `json:"mean_score,omitempty"`
+}
+
+// YearActivity counts the anime started and finished in a year, by the
+// dates on the list. Entries without dates are not counted.
+type YearActivity struct {
+	Year      int `json:"year"`
+	Started   int `json:"started"`
+	Completed int `json:"completed"`
+}
+
+// Stats fetches a user's whole anime list and summarizes it
+func (l *UserListService) Stats(userName string) (*ListStats, error) {
+	return l.StatsContext(context.Background(), userName)
+}
+
+// StatsContext is like Stats but uses ctx for its requests
+func (l *UserListService) StatsContext(ctx context.Context, userName string) (*ListStats, error) {
+	entries := []AnimeListEntry{}
+	for entry, err := range l.AnimeListAllContext(ctx, userName, AnimeListOptions{Limit: 1000, Fields: statsFields}, 0) {
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return ComputeListStats(entries), nil
+}
+
+// tally counts names and sums the scores given to them
+type tally struct {
+	counts map[string]int
+	scores map[string]int
+	scored map[string]int
+}
+
+func newTally() *tally {
+	return &tally{counts: map[string]int{}, scores: map[string]int{}, scored: map[string]int{}}
+}
+
+func (t *tally) add(name string, score int) {
+	t.counts[name]++
+	if score > 0 {
+		t.scores[name] += score
+		t.scored[name]++
+	}
+}
+
+// top returns up to n names by count, all of them if n is 0
+func (t *tally) top(n int) []StatCount {
+	stats := make([]StatCount, 0, len(t.counts))
+	for name, count := range t.counts {
+		stat := StatCount{Name: name, Count: count}
+		if t.scored[name] > 0 {
+			stat.MeanScore = round2(float64(t.scores[name]) / float64(t.scored[name]))
+		}
+		stats = append(stats, stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Count != stats[j].Count {
+			return stats[i].Count > stats[j].Count
+		}
+		return stats[i].Name < stats[j].Name
+	})
+	if n > 0 && len(stats) > n {
+		stats = stats[:n]
+	}
+	return stats
+}
+
+// ComputeListStats summarizes list entries. Genres, studios, episode
+// durations and community scores are only counted when the entries were
+// fetched with those fields.
+func ComputeListStats(entries []AnimeListEntry) *ListStats {
+	stats := &ListStats{Entries: len(entries)}
+	statuses, mediaTypes, genres, studios := newTally(), newTally(), newTally(), newTally()
+	scoreCounts := make([]int, 11)
+	years := make(map[int]*YearActivity)
+	year := func(date string) *YearActivity {
+		if len(date) < 4 {
+			return nil
+		}
+		y, err := strconv.Atoi(date[:4])
+		if err != nil {
+			return nil
+		}
+		if years[y] == nil {
+			years[y] = &YearActivity{Year: y}
+		}
+		return years[y]
+	}
+
+	var started, completed, scored, scoreSum, compared int
+	var deviation, absDeviation float64
+	watchSeconds := 0
+	for _, entry := range entries {
+		anime, status := entry.Node, entry.ListStatus
+		score := status.Score
+
+		statuses.add(status.Status, score)
+		if anime.MediaType != "" {
+			mediaTypes.add(anime.MediaType, score)
+		}
+		for _, g := range anime.Genres {
+			genres.add(g.Name, score)
+		}
+		for _, s := range anime.Studios {
+			studios.add(s.Name, score)
+		}
+
+		stats.EpisodesWatched += status.NumEpisodesWatched
+		watchSeconds += status.NumEpisodesWatched * anime.AverageEpisodeDuration
+		if status.Status != "plan_to_watch" {
+			started++
+		}
+		if status.Status == "completed" {
+			completed++
+		}
+
+		if score >= 1 && score <= 10 {
+			scored++
+			scoreSum += score
+			scoreCounts[score]++
+			if anime.Mean > 0 {
+				compared++
+				deviation += float64(score) - anime.Mean
+				absDeviation += math.Abs(float64(score) - anime.Mean)
+			}
+		}
+
+		if y := year(status.StartDate); y != nil {
+			y.Started++
+		}
+		if y := year(status.FinishDate); y != nil && status.Status == "completed" {
+			y.Completed++
+		}
+	}
+
+	stats.WatchMinutes = watchSeconds / 60
+	if started > 0 {
+		stats.CompletionRate = round2(float64(completed) / float64(started))
+	}
+	if scored > 0 {
+		stats.MeanScore = round2(float64(scoreSum) / float64(scored))
+	}
+	if compared > 0 {
+		stats.MeanDeviation = round2(deviation / float64(compared))
+		stats.MeanAbsoluteDeviation = round2(absDeviation / float64(compared))
+	}
+	for score := 1; score <= 10; score++ {
+		stats.ScoreDistribution = append(stats.ScoreDistribution, ScoreCount{Score: score, Count: scoreCounts[score]})
+	}
+
+	stats.Statuses = statuses.top(0)
+	stats.MediaTypes = mediaTypes.top(0)
+	stats.TopGenres = genres.top(statsTopN)
+	stats.TopStudios = studios.top(statsTopN)
+
+	stats.Years = []YearActivity{}
+	for _, y := range years {
+		stats.Years = append(stats.Years, *y)
+	}
+	sort.Slice(stats.Years, func(i, j int) bool { return stats.Years[i].Year < stats.Years[j].Year })
+	return stats
+}
+
+func round2(x float64) float64 {
+	return math.Round(x*100) / 100
+}
@@ -0,0 +1,77 @@
+package mal
+
+import "fmt"
+
+// TitleLanguage selects which of an entry's titles to show
+type TitleLanguage string
+
+const (
+	// TitleRomaji is MAL's main title, usually the romanized Japanese title
+	TitleRomaji  TitleLanguage = "romaji"
+	TitleEnglish TitleLanguage = "english"
+	// TitleNative is the title in its original script, e.g. 葬送のフリーレン
+	TitleNative TitleLanguage = "native"
+)
+
+func ValidateTitleLanguage(lang string) error {
+	switch TitleLanguage(lang) {
+	case TitleRomaji, TitleEnglish, TitleNative:
+		return nil
+	}
+	return fmt.Errorf("invalid title language: %s", lang)
+}
+
+// titleFallbacks is the order languages are tried in when an entry has no
+// title in the preferred one. MAL always has a romaji title.
+var titleFallbacks = map[TitleLanguage][]TitleLanguage{
+	TitleRomaji:  {TitleRomaji},
+	TitleEnglish: {TitleEnglish, TitleRomaji},
+	TitleNative:  {TitleNative, TitleRomaji},
+}
+
+// ResolveTitle picks the title to show for an entry with the given main
+// title and alternative titles. If the entry has no title in lang, the
+// romaji title is used. An empty lang means romaji.
+//
+// English and native titles are only available when alternative_titles
+// was requested.
+func ResolveTitle(title string, alt AlternativeTitles, lang TitleLanguage) string {
+	for _, l := range titleFallbacks[lang] {
+		switch l {
+		case TitleEnglish:
+			if alt.En != "" {
+				return alt.En
+			}
+		case TitleNative:
+			if alt.Ja != "" {
+				return alt.Ja
+			}
+		}
+	}
+	return title
+}
+
+// PreferredTitle returns the anime's title in lang; see ResolveTitle
+func (a *AnimeDetails) PreferredTitle(lang TitleLanguage) string {
+	return ResolveTitle(a.Title, a.AlternativeTitles, lang)
+}
+
+// PreferredTitle returns the anime's title in lang; see ResolveTitle
+func (n *AnimeNode) PreferredTitle(lang TitleLanguage) string {
+	return ResolveTitle(n.Title, n.AlternativeTitles, lang)
+}
+
+// PreferredTitle returns the manga's title in lang; see ResolveTitle
+func (m *MangaDetails) PreferredTitle(lang TitleLanguage) string {
+	return ResolveTitle(m.Title, m.AlternativeTitles, lang)
+}
+
+// PreferredTitle returns the manga's title in lang; see ResolveTitle
+func (n *MangaNode) PreferredTitle(lang TitleLanguage) string {
+	return ResolveTitle(n.Title, n.AlternativeTitles, lang)
+}
+
+// SetDisplayTitle fills DisplayTitle with the anime's title in lang
+func (a *AnimeDetails) SetDisplayTitle(lang TitleLanguage) {
+	a.DisplayTitle = a.PreferredTitle(lang)
+}
@@ -0,0 +1,52 @@
+package mal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type UserService struct {
+	client *Client
+}
+
+// User is the authenticated user's MAL profile
+type User struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Picture  string `json:"picture,omitempty"`
+	JoinedAt string `json:"joined_at,omitempty"`
+}
+
+// Me returns the profile of the logged-in user. It requires an access token.
+func (u *UserService) Me() (*User, error) {
+	return u.MeContext(context.Background())
+}
+
+// MeContext is like Me but uses ctx for the request
+func (u *UserService) MeContext(ctx context.Context) (*User, error) {
+	reqURL := u.client.baseURL.String() + "users/@me"
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var user User
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &user, nil
+}
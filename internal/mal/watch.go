@@ -0,0 +1,138 @@
+package mal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrAlreadyCompleted is returned when recording progress on a completed
+// anime outside of a rewatch
+var ErrAlreadyCompleted = errors.New("anime is already completed")
+
+// WatchOptions controls how Watch records progress
+type WatchOptions struct {
+	// To sets the episode count directly instead of adding one
+	To int
+	// Rewatch records progress against a rewatch of a completed anime
+	Rewatch bool
+	// Now is used for start and finish dates; defaults to time.Now
+	Now time.Time
+}
+
+// WatchResult describes the list entry before and after Watch
+type WatchResult struct {
+	Title             string            `json:"title"`
+	AlternativeTitles AlternativeTitles `json:"alternative_titles"`
+	NumEpisodes       int               `json:"num_episodes"`
+	Previous          *AnimeListStatus  `json:"previous"`
+	Current           *AnimeListStatus  `json:"current"`
+}
+
+// PreferredTitle returns the anime's title in lang; see ResolveTitle
+func (r *WatchResult) PreferredTitle(lang TitleLanguage) string {
+	return ResolveTitle(r.Title, r.AlternativeTitles, lang)
+}
+
+// Watch records that the logged-in user watched the next episode of an anime.
+// Status moves from plan_to_watch (or not listed) to watching, and to
+// completed when the last episode is reached. Start and finish dates are
+// filled in if they are not already set.
+func (l *UserListService) Watch(animeID int, opts WatchOptions) (*WatchResult, error) {
+	return l.WatchContext(context.Background(), animeID, opts)
+}
+
+// WatchContext is like Watch but uses ctx for its requests
+func (l *UserListService) WatchContext(ctx context.Context, animeID int, opts WatchOptions) (*WatchResult, error) {
+	details, err := l.client.Anime.DetailsContext(ctx, animeID, "id", "title", "alternative_titles", "num_episodes")
+	if err != nil {
+		return nil, err
+	}
+	previous, err := l.AnimeStatusContext(ctx, animeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	update, err := NextWatchUpdate(previous, details.NumEpisodes, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := l.UpdateAnimeContext(ctx, animeID, update)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WatchResult{
+		Title:             details.Title,
+		AlternativeTitles: details.AlternativeTitles,
+		NumEpisodes:       details.NumEpisodes,
+		Previous:          previous,
+		Current:           current,
+	}, nil
+}
+
+// NextWatchUpdate computes the list update for watching the next episode
+// given the current status (nil if not listed) and the total episode count
+// (0 if unknown).
+func NextWatchUpdate(current *AnimeListStatus, numEpisodes int, opts WatchOptions) (AnimeListUpdate, error) {
+	var status AnimeListStatus
+	if current != nil {
+		status = *current
+	}
+	today := opts.Now.Format("2006-01-02")
+	var update AnimeListUpdate
+
+	rewatching := status.IsRewatching || opts.Rewatch
+	if rewatching && status.Status != "completed" {
+		return update, fmt.Errorf("only completed anime can be rewatched, status is %q", status.Status)
+	}
+	if status.Status == "completed" && !rewatching {
+		return update, ErrAlreadyCompleted
+	}
+
+	watched := status.NumEpisodesWatched
+	if rewatching && !status.IsRewatching {
+		// Starting a new rewatch restarts the episode count
+		watched = 0
+		update.IsRewatching = boolPtr(true)
+	}
+
+	target := watched + 1
+	if opts.To > 0 {
+		target = opts.To
+	}
+	if numEpisodes > 0 && target > numEpisodes {
+		return update, fmt.Errorf("episode %d is past the last episode (%d)", target, numEpisodes)
+	}
+	update.NumWatchedEpisodes = &target
+
+	finished := numEpisodes > 0 && target == numEpisodes
+	switch {
+	case rewatching && finished:
+		update.IsRewatching = boolPtr(false)
+		update.NumTimesRewatched = intPtr(status.NumTimesRewatched + 1)
+	case rewatching:
+		// Progress is tracked in num_watched_episodes while is_rewatching is set
+	case finished:
+		update.Status = stringPtr("completed")
+		if status.FinishDate == "" {
+			update.FinishDate = &today
+		}
+	case status.Status == "" || status.Status == "plan_to_watch":
+		update.Status = stringPtr("watching")
+	}
+
+	if !rewatching && status.StartDate == "" {
+		update.StartDate = &today
+	}
+	return update, nil
+}
+
+func boolPtr(b bool) *bool       { return &b }
+func intPtr(i int) *int          { return &i }
+func stringPtr(s string) *string { return &s }
@@ -0,0 +1,172 @@
+package mal
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNextWatchUpdate(t *testing.T) {
+	now := time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC)
+	today := "2024-03-09"
+
+	tests := []struct {
+		name        string
+		current     *AnimeListStatus
+		numEpisodes int
+		opts        WatchOptions
+		want        AnimeListUpdate
+		wantErr     bool
+	}{
+		{
+			name:        "first episode of an unlisted anime",
+			numEpisodes: 12,
+			want: AnimeListUpdate{
+				Status:             stringPtr("watching"),
+				NumWatchedEpisodes: intPtr(1),
+				StartDate:          &today,
+			},
+		},
+		{
+			name:        "plan to watch starts watching",
+			current:     &AnimeListStatus{Status: "plan_to_watch"},
+			numEpisodes: 12,
+			want: AnimeListUpdate{
+				Status:             stringPtr("watching"),
+				NumWatchedEpisodes: intPtr(1),
+				StartDate:          &today,
+			},
+		},
+		{
+			name:        "start date already set",
+			current:     &AnimeListStatus{Status: "watching", NumEpisodesWatched: 3, StartDate: "2024-01-01"},
+			numEpisodes: 12,
+			want:        AnimeListUpdate{NumWatchedEpisodes: intPtr(4)},
+		},
+		{
+			name:        "last episode completes",
+			current:     &AnimeListStatus{Status: "watching", NumEpisodesWatched: 11, StartDate: "2024-01-01"},
+			numEpisodes: 12,
+			want: AnimeListUpdate{
+				Status:             stringPtr("completed"),
+				NumWatchedEpisodes: intPtr(12),
+				FinishDate:         &today,
+			},
+		},
+		{
+			name:        "finish date already set",
+			current:     &AnimeListStatus{Status: "watching", NumEpisodesWatched: 11, StartDate: "2024-01-01", FinishDate: "2024-02-01"},
+			numEpisodes: 12,
+			want: AnimeListUpdate{
+				Status:             stringPtr("completed"),
+				NumWatchedEpisodes: intPtr(12),
+			},
+		},
+		{
+			name:    "unknown episode count never completes",
+			current: &AnimeListStatus{Status: "watching", NumEpisodesWatched: 500, StartDate: "2024-01-01"},
+			want:    AnimeListUpdate{NumWatchedEpisodes: intPtr(501)},
+		},
+		{
+			name:        "to sets the count",
+			current:     &AnimeListStatus{Status: "watching", NumEpisodesWatched: 2, StartDate: "2024-01-01"},
+			numEpisodes: 12,
+			opts:        WatchOptions{To: 12},
+			want: AnimeListUpdate{
+				Status:             stringPtr("completed"),
+				NumWatchedEpisodes: intPtr(12),
+				FinishDate:         &today,
+			},
+		},
+		{
+			name:        "past the last episode",
+			current:     &AnimeListStatus{Status: "watching", NumEpisodesWatched: 12},
+			numEpisodes: 12,
+			wantErr:     true,
+		},
+		{
+			name:        "to past the last episode",
+			numEpisodes: 12,
+			opts:        WatchOptions{To: 13},
+			wantErr:     true,
+		},
+		{
+			name:        "completed without rewatch",
+			current:     &AnimeListStatus{Status: "completed", NumEpisodesWatched: 12},
+			numEpisodes: 12,
+			wantErr:     true,
+		},
+		{
+			name:        "rewatch of an anime that is not completed",
+			current:     &AnimeListStatus{Status: "watching", NumEpisodesWatched: 3},
+			numEpisodes: 12,
+			opts:        WatchOptions{Rewatch: true},
+			wantErr:     true,
+		},
+		{
+			name:        "rewatch starts over",
+			current:     &AnimeListStatus{Status: "completed", NumEpisodesWatched: 12, StartDate: "2024-01-01", FinishDate: "2024-02-01"},
+			numEpisodes: 12,
+			opts:        WatchOptions{Rewatch: true},
+			want: AnimeListUpdate{
+				NumWatchedEpisodes: intPtr(1),
+				IsRewatching:       boolPtr(true),
+			},
+		},
+		{
+			name:        "rewatch continues",
+			current:     &AnimeListStatus{Status: "completed", NumEpisodesWatched: 4, IsRewatching: true},
+			numEpisodes: 12,
+			want:        AnimeListUpdate{NumWatchedEpisodes: intPtr(5)},
+		},
+		{
+			name:        "rewatch completes",
+			current:     &AnimeListStatus{Status: "completed", NumEpisodesWatched: 11, IsRewatching: true, NumTimesRewatched: 1},
+			numEpisodes: 12,
+			want: AnimeListUpdate{
+				NumWatchedEpisodes: intPtr(12),
+				IsRewatching:       boolPtr(false),
+				NumTimesRewatched:  intPtr(2),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Now = now
+			got, err := NextWatchUpdate(tt.current, tt.numEpisodes, tt.opts)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NextWatchUpdate() = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NextWatchUpdate() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NextWatchUpdate() = %s, want %s", describeUpdate(got), describeUpdate(tt.want))
+			}
+		})
+	}
+}
+
+func TestNextWatchUpdateAlreadyCompleted(t *testing.T) {
+	_, err := NextWatchUpdate(&AnimeListStatus{Status: "completed"}, 12, WatchOptions{Now: time.Now()})
+	if !errors.Is(err, ErrAlreadyCompleted) {
+		t.Errorf("error = %v, want ErrAlreadyCompleted", err)
+	}
+}
+
+// describeUpdate prints the fields an update sets, for failure messages
+func describeUpdate(u AnimeListUpdate) string {
+	s := "{"
+	v := reflect.ValueOf(u)
+	for i := range v.NumField() {
+		if f := v.Field(i); !f.IsNil() {
+			s += fmt.Sprintf(" %s=%v", v.Type().Field(i).Name, f.Elem().Interface())
+		}
+	}
+	return s + " }"
+}
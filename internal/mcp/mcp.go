@@ -3,6 +3,9 @@ package mcp
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/bradleyyma/zutto/internal/mal"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -12,16 +15,18 @@ import (
 type Server struct {
 	mcpServer *mcp.Server
 	malClient *mal.Client
-}
+	// anime answers the search, details and resolve tools; MAL by default
+	anime mal.AnimeSource
 
-// NewMCPServer creates and configures the MCP server with all tools
-func NewMCPServer() (*Server, error) {
-	// Initialize MAL client - it will automatically get clientID from MAL_CLIENT_ID env var
-	malClient := mal.NewClient(nil, "")
+	// titleLanguage is used when a tool call does not choose one
+	titleLanguage mal.TitleLanguage
+}
 
-	// Validate that we have a client ID
+// NewMCPServer creates and configures the MCP server with all tools.
+// If malClient is nil, a client is created from the MAL_CLIENT_ID env var.
+func NewMCPServer(malClient *mal.Client) (*Server, error) {
 	if malClient == nil {
-		return nil, fmt.Errorf("failed to create MAL client")
+		malClient = mal.NewClient(nil, "")
 	}
 
 	// Create MCP server
@@ -36,6 +41,7 @@ func NewMCPServer() (*Server, error) {
 	server := &Server{
 		mcpServer: mcpServer,
 		malClient: malClient,
+		anime:     malClient.Anime,
 	}
 
 	// Register tools
@@ -46,6 +52,19 @@ func NewMCPServer() (*Server, error) {
 	return server, nil
 }
 
+// SetTitleLanguage sets the language display_title is filled in when a
+// tool call does not pass title_language. By default display_title is
+// only filled on request.
+func (s *Server) SetTitleLanguage(lang mal.TitleLanguage) {
+	s.titleLanguage = lang
+}
+
+// SetAnimeSource makes the search, details and resolve tools read from src,
+// e.g. the local mirror, instead of MAL
+func (s *Server) SetAnimeSource(src mal.AnimeSource) {
+	s.anime = src
+}
+
 // registerTools registers all available MCP tools
 func (s *Server) registerTools() error {
 	// Register the anime ranking tool using the generic AddTool
@@ -80,11 +99,38 @@ func (s *Server) registerTools() error {
 		s.mcpServer,
 		&mcp.Tool{
 			Name:        "batch_get_anime_details",
-			Description: "Get detailed information about multiple animes, providing at least one MyAnimelistID",
+			Description: "Get detailed information about multiple animes, providing at least one MyAnimelistID. IDs that cannot be fetched are listed in errors while the rest are still returned",
 		},
 		s.handleBatchAnimeDetails,
 	)
 
+	mcp.AddTool(
+		s.mcpServer,
+		&mcp.Tool{
+			Name:        "get_seasonal_anime",
+			Description: "Get anime that started airing in a season on MyAnimeList. Defaults to the current season",
+		},
+		s.handleSeasonalAnime,
+	)
+
+	mcp.AddTool(
+		s.mcpServer,
+		&mcp.Tool{
+			Name:        "resolve_anime",
+			Description: "Find the MyAnimeList ID for an anime name. Candidates are ranked by how closely their titles, English and Japanese titles or synonyms match; match is set only when one candidate is a clear winner, otherwise ask the user which candidate they mean",
+		},
+		s.handleResolveAnime,
+	)
+
+	mcp.AddTool(
+		s.mcpServer,
+		&mcp.Tool{
+			Name:        "recommend_anime",
+			Description: "Recommend anime for a user from the MyAnimeList recommendations of their highest-scored completed anime, weighted by their score and the number of recommendations, plus MyAnimeList's personalized suggestions for the logged-in user. Anime already on the user's list are left out, and each recommendation lists the reasons it was made",
+		},
+		s.handleRecommendAnime,
+	)
+
 	return nil
 }
 
@@ -112,11 +158,22 @@ func (s *Server) handleGetAnimeRanking(
 	if err := mal.ValidateAnimeRankingType(input.RankingType); err != nil {
 		return nil, mal.AnimeRankingResponse{}, err
 	}
+	if err := mal.ValidateAnimeFields(input.Fields); err != nil {
+		return nil, mal.AnimeRankingResponse{}, err
+	}
+	lang, err := s.resolveTitleLanguage(input.TitleLanguage)
+	if err != nil {
+		return nil, mal.AnimeRankingResponse{}, err
+	}
+	fields := withTitleFields(input.Fields, mal.DefaultAnimeListFields, lang)
 
 	// Call MAL API
-	rankings, err := s.malClient.Anime.Rankings(input.RankingType, input.Limit, input.Offset)
+	rankings, err := s.malClient.Anime.RankingsContext(ctx, input.RankingType, input.Limit, input.Offset, fields...)
 	if err != nil {
-		return nil, mal.AnimeRankingResponse{}, fmt.Errorf("failed to fetch rankings: %w", err)
+		return nil, mal.AnimeRankingResponse{}, toolError("failed to fetch rankings", err)
+	}
+	for i := range rankings.Data {
+		setDisplayTitle(&rankings.Data[i].Node, lang)
 	}
 
 	return nil, *rankings, nil
@@ -130,11 +187,23 @@ func (s *Server) handleAnimeDetails(
 	if input.ID <= 0 {
 		return nil, mal.AnimeDetails{}, fmt.Errorf("invalid anime ID")
 	}
+	if err := mal.ValidateAnimeFields(input.Fields); err != nil {
+		return nil, mal.AnimeDetails{}, err
+	}
+	lang, err := s.resolveTitleLanguage(input.TitleLanguage)
+	if err != nil {
+		return nil, mal.AnimeDetails{}, err
+	}
+	fields := withTitleFields(input.Fields, mal.DefaultAnimeDetailsFields, lang)
 
-	details, err := s.malClient.Anime.Details(input.ID)
+	details, err := s.anime.DetailsContext(ctx, input.ID, fields...)
+	if mal.IsNotFound(err) {
+		return nil, mal.AnimeDetails{}, fmt.Errorf("no anime with ID %d", input.ID)
+	}
 	if err != nil {
-		return nil, mal.AnimeDetails{}, fmt.Errorf("failed to fetch anime details: %w", err)
+		return nil, mal.AnimeDetails{}, toolError("failed to fetch anime details", err)
 	}
+	setDisplayTitle(details, lang)
 
 	return nil, *details, nil
 }
@@ -152,13 +221,37 @@ func (s *Server) handleBatchAnimeDetails(
 			return nil, BatchDetailsOutput{}, fmt.Errorf("invalid anime ID %d", id)
 		}
 	}
+	if err := mal.ValidateAnimeFields(input.Fields); err != nil {
+		return nil, BatchDetailsOutput{}, err
+	}
+	lang, err := s.resolveTitleLanguage(input.TitleLanguage)
+	if err != nil {
+		return nil, BatchDetailsOutput{}, err
+	}
+	fields := withTitleFields(input.Fields, mal.DefaultAnimeDetailsFields, lang)
 
-	details, err := s.malClient.Anime.BatchDetails(input.IDs)
+	batch, err := s.anime.BatchDetailsContext(ctx, input.IDs, fields...)
 	if err != nil {
-		return nil, BatchDetailsOutput{}, fmt.Errorf("failed to batch fetch anime details: %w", err)
+		return nil, BatchDetailsOutput{}, toolError("failed to batch fetch anime details", err)
 	}
+	for i := range batch.Results {
+		setDisplayTitle(&batch.Results[i], lang)
+	}
+
+	// Report failed IDs alongside the successful ones instead of failing
+	// the whole call
 	batchDetails := BatchDetailsOutput{
-		Details: details,
+		Details: batch.Results,
+	}
+	for _, batchErr := range batch.Errors {
+		message := toolError("failed to fetch anime details", batchErr.Err).Error()
+		if mal.IsNotFound(batchErr.Err) {
+			message = fmt.Sprintf("no anime with ID %d", batchErr.ID)
+		}
+		batchDetails.Errors = append(batchDetails.Errors, BatchDetailsError{
+			ID:    batchErr.ID,
+			Error: message,
+		})
 	}
 	return nil, batchDetails, nil
 }
@@ -178,15 +271,217 @@ func (s *Server) handleAnimeSearch(
 	if input.Query == "" {
 		return nil, mal.AnimeSearchResponse{}, fmt.Errorf("query cannot be empty")
 	}
+	if err := mal.ValidateAnimeFields(input.Fields); err != nil {
+		return nil, mal.AnimeSearchResponse{}, err
+	}
+	lang, err := s.resolveTitleLanguage(input.TitleLanguage)
+	if err != nil {
+		return nil, mal.AnimeSearchResponse{}, err
+	}
+	fields := withTitleFields(input.Fields, mal.DefaultAnimeListFields, lang)
 
-	results, err := s.malClient.Anime.Search(input.Query, input.Limit)
+	results, err := s.anime.SearchContext(ctx, input.Query, input.Limit, fields...)
 	if err != nil {
-		return nil, mal.AnimeSearchResponse{}, fmt.Errorf("failed to fetch anime search results: %w", err)
+		return nil, mal.AnimeSearchResponse{}, toolError("failed to fetch anime search results", err)
+	}
+	for i := range results.Data {
+		setDisplayTitle(&results.Data[i].Node, lang)
 	}
 
 	return nil, *results, nil
 }
 
+// handleSeasonalAnime handles the get_seasonal_anime tool invocation
+func (s *Server) handleSeasonalAnime(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input SeasonalInput,
+) (*mcp.CallToolResult, mal.AnimeSeasonResponse, error) {
+	// Apply defaults
+	current := mal.CurrentSeason(time.Now())
+	if input.Year == 0 {
+		input.Year = current.Year
+	}
+	if input.Season == "" {
+		input.Season = current.Season
+	}
+	if input.Sort == "" {
+		input.Sort = "anime_score"
+	}
+	if input.Limit == 0 {
+		input.Limit = 50
+	}
+	if input.Limit < 1 || input.Limit > 500 {
+		return nil, mal.AnimeSeasonResponse{}, fmt.Errorf("limit must be between 1 and 500")
+	}
+	if input.Offset < 0 {
+		input.Offset = 0
+	}
+
+	if err := mal.ValidateSeason(input.Season); err != nil {
+		return nil, mal.AnimeSeasonResponse{}, err
+	}
+	if err := mal.ValidateSeasonalSort(input.Sort); err != nil {
+		return nil, mal.AnimeSeasonResponse{}, err
+	}
+	if err := mal.ValidateAnimeMediaTypes(input.MediaTypes); err != nil {
+		return nil, mal.AnimeSeasonResponse{}, err
+	}
+	if err := mal.ValidateAnimeFields(input.Fields); err != nil {
+		return nil, mal.AnimeSeasonResponse{}, err
+	}
+	lang, err := s.resolveTitleLanguage(input.TitleLanguage)
+	if err != nil {
+		return nil, mal.AnimeSeasonResponse{}, err
+	}
+
+	fields := withTitleFields(input.Fields, mal.DefaultAnimeListFields, lang)
+	seasonal, err := s.malClient.Anime.SeasonalOfTypesContext(ctx, input.Year, input.Season, input.Sort, input.Limit, input.Offset, input.MediaTypes, fields...)
+	if err != nil {
+		return nil, mal.AnimeSeasonResponse{}, toolError("failed to fetch seasonal anime", err)
+	}
+	for i := range seasonal.Data {
+		setDisplayTitle(&seasonal.Data[i].Node, lang)
+	}
+
+	return nil, *seasonal, nil
+}
+
+// handleResolveAnime handles the resolve_anime tool invocation
+func (s *Server) handleResolveAnime(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input ResolveInput,
+) (*mcp.CallToolResult, ResolveOutput, error) {
+	if input.Limit == 0 {
+		input.Limit = 10
+	}
+	if input.Limit < 1 || input.Limit > 50 {
+		return nil, ResolveOutput{}, fmt.Errorf("limit must be between 1 and 50")
+	}
+	if strings.TrimSpace(input.Name) == "" {
+		return nil, ResolveOutput{}, fmt.Errorf("name cannot be empty")
+	}
+	lang, err := s.resolveTitleLanguage(input.TitleLanguage)
+	if err != nil {
+		return nil, ResolveOutput{}, err
+	}
+
+	resolution, err := mal.ResolveAnime(ctx, s.anime, input.Name, input.Limit)
+	if err != nil {
+		return nil, ResolveOutput{}, toolError("failed to resolve anime name", err)
+	}
+	for i := range resolution.Candidates {
+		setDisplayTitle(&resolution.Candidates[i].Anime, lang)
+	}
+
+	output := ResolveOutput{
+		Query:      resolution.Query,
+		Candidates: resolution.Candidates,
+	}
+	if output.Candidates == nil {
+		output.Candidates = []mal.AnimeCandidate{}
+	}
+	if best, ok := resolution.Best(); ok {
+		output.Match = best
+	}
+	return nil, output, nil
+}
+
+// handleRecommendAnime handles the recommend_anime tool invocation
+func (s *Server) handleRecommendAnime(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input RecommendInput,
+) (*mcp.CallToolResult, RecommendOutput, error) {
+	if input.Seeds < 0 || input.Seeds > 100 {
+		return nil, RecommendOutput{}, fmt.Errorf("seeds must be 0 (default) to 100")
+	}
+	if input.Limit < 0 || input.Limit > 100 {
+		return nil, RecommendOutput{}, fmt.Errorf("limit must be 0 (default) to 100")
+	}
+	lang, err := s.resolveTitleLanguage(input.TitleLanguage)
+	if err != nil {
+		return nil, RecommendOutput{}, err
+	}
+
+	result, err := s.malClient.List.RecommendContext(ctx, mal.RecommendOptions{
+		User:          input.User,
+		Seeds:         input.Seeds,
+		Limit:         input.Limit,
+		NoSuggestions: input.NoSuggestions,
+	})
+	if err != nil {
+		return nil, RecommendOutput{}, toolError("failed to get recommendations", err)
+	}
+	for i := range result.Recommendations {
+		setDisplayTitle(&result.Recommendations[i].Anime, lang)
+	}
+
+	output := RecommendOutput{
+		Seeds:           result.Seeds,
+		Recommendations: result.Recommendations,
+	}
+	for _, seedErr := range result.Errors {
+		output.Errors = append(output.Errors, BatchDetailsError{
+			ID:    seedErr.ID,
+			Error: toolError("failed to fetch recommendations", seedErr.Err).Error(),
+		})
+	}
+	if result.SuggestionsErr != nil {
+		output.Warnings = append(output.Warnings, toolError("left out MyAnimeList's suggestions", result.SuggestionsErr).Error())
+	}
+	return nil, output, nil
+}
+
+// resolveTitleLanguage validates the title language a tool call asked for,
+// falling back to the server's default
+func (s *Server) resolveTitleLanguage(lang string) (mal.TitleLanguage, error) {
+	if lang == "" {
+		return s.titleLanguage, nil
+	}
+	if err := mal.ValidateTitleLanguage(lang); err != nil {
+		return "", err
+	}
+	return mal.TitleLanguage(lang), nil
+}
+
+// withTitleFields makes sure alternative titles are requested when a
+// display title is wanted
+func withTitleFields(fields []string, defaults string, lang mal.TitleLanguage) []string {
+	if lang == "" || slices.Contains(fields, "alternative_titles") {
+		return fields
+	}
+	if len(fields) == 0 {
+		if strings.Contains(defaults, "alternative_titles") {
+			return fields
+		}
+		fields = []string{defaults}
+	}
+	return append(fields, "alternative_titles")
+}
+
+// setDisplayTitle fills display_title when a title language was chosen
+func setDisplayTitle(anime *mal.AnimeDetails, lang mal.TitleLanguage) {
+	if lang != "" {
+		anime.SetDisplayTitle(lang)
+	}
+}
+
+// toolError wraps err for the tool caller, explaining well-known MAL API
+// failures in plain terms
+func toolError(action string, err error) error {
+	switch {
+	case mal.IsUnauthorized(err):
+		return fmt.Errorf("%s: MyAnimeList rejected the client ID or access token", action)
+	case mal.IsForbidden(err):
+		return fmt.Errorf("%s: MyAnimeList denied access to this resource", action)
+	case mal.IsRateLimited(err):
+		return fmt.Errorf("%s: MyAnimeList is rate limiting requests, retry later", action)
+	}
+	return fmt.Errorf("%s: %w", action, err)
+}
+
 // Run starts the MCP server
 func (s *Server) Run(ctx context.Context) error {
 	return s.mcpServer.Run(ctx, &mcp.StdioTransport{})
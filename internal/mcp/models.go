@@ -4,24 +4,79 @@ import "github.com/bradleyyma/zutto/internal/mal"
 
 // RankingInput defines the input parameters for the get_anime_ranking tool
 type RankingInput struct {
-	RankingType string `json:"ranking_type" jsonschema:"Type of ranking (all, tv, movie, ova, ona, special, bypopularity, favorite)"`
-	Limit       int    `json:"limit" jsonschema:"Maximum number of results to return (1-100)"`
-	Offset      int    `json:"offset" jsonschema:"Offset for pagination"`
+	RankingType   string   `json:"ranking_type" jsonschema:"Type of ranking (all, airing, upcoming, tv, movie, ova, ona, special, bypopularity, favorite)"`
+	Limit         int      `json:"limit" jsonschema:"Maximum number of results to return (1-100)"`
+	Offset        int      `json:"offset" jsonschema:"Offset for pagination"`
+	Fields        []string `json:"fields,omitempty" jsonschema:"MyAnimeList fields to include for each anime, e.g. mean, genres, num_episodes"`
+	TitleLanguage string   `json:"title_language,omitempty" jsonschema:"Fill display_title with the title in this language (romaji, english, native), falling back to the romaji title"`
 }
 
 type DetailsInput struct {
-	ID int `json:"id" jsonschema:"ID of the anime to get details for"`
+	ID            int      `json:"id" jsonschema:"ID of the anime to get details for"`
+	Fields        []string `json:"fields,omitempty" jsonschema:"MyAnimeList fields to include, e.g. genres, studios, related_anime, statistics. Defaults to a basic summary"`
+	TitleLanguage string   `json:"title_language,omitempty" jsonschema:"Fill display_title with the title in this language (romaji, english, native), falling back to the romaji title"`
 }
 
 type BatchDetailsInput struct {
-	IDs []int `json:"ids" jsonschema:"IDs of the animes to get details for"`
+	IDs           []int    `json:"ids" jsonschema:"IDs of the animes to get details for"`
+	Fields        []string `json:"fields,omitempty" jsonschema:"MyAnimeList fields to include for each anime. Defaults to a basic summary"`
+	TitleLanguage string   `json:"title_language,omitempty" jsonschema:"Fill display_title with the title in this language (romaji, english, native), falling back to the romaji title"`
 }
 
 type BatchDetailsOutput struct {
-	Details []mal.AnimeDetails
+	Details []mal.AnimeDetails  `json:"details"`
+	Errors  []BatchDetailsError `json:"errors,omitempty" jsonschema:"IDs that could not be fetched, with the reason"`
+}
+
+type BatchDetailsError struct {
+	ID    int    `json:"id"`
+	Error string `json:"error"`
 }
 
 type SearchInput struct {
-	Query string `json:"query" jsonschema:"Search query for the anime"`
-	Limit int    `json:"limit" jsonschema:"Maximum number of results to return (1-50)"`
+	Query         string   `json:"query" jsonschema:"Search query for the anime"`
+	Limit         int      `json:"limit" jsonschema:"Maximum number of results to return (1-50)"`
+	Fields        []string `json:"fields,omitempty" jsonschema:"MyAnimeList fields to include for each anime, e.g. mean, media_type, start_season"`
+	TitleLanguage string   `json:"title_language,omitempty" jsonschema:"Fill display_title with the title in this language (romaji, english, native), falling back to the romaji title"`
+}
+
+// SeasonalInput defines the input parameters for the get_seasonal_anime tool
+type SeasonalInput struct {
+	Year          int      `json:"year,omitempty" jsonschema:"Year of the season. Defaults to the current year"`
+	Season        string   `json:"season,omitempty" jsonschema:"Season (winter, spring, summer, fall). Defaults to the current season"`
+	Sort          string   `json:"sort,omitempty" jsonschema:"Sort order (anime_score, anime_num_list_users)"`
+	MediaTypes    []string `json:"media_types,omitempty" jsonschema:"Only return these media types (tv, movie, ova, ona, special, music)"`
+	Limit         int      `json:"limit" jsonschema:"Maximum number of results to return (1-500)"`
+	Offset        int      `json:"offset" jsonschema:"Offset for pagination"`
+	Fields        []string `json:"fields,omitempty" jsonschema:"MyAnimeList fields to include for each anime, e.g. mean, genres, num_episodes"`
+	TitleLanguage string   `json:"title_language,omitempty" jsonschema:"Fill display_title with the title in this language (romaji, english, native), falling back to the romaji title"`
+}
+
+// ResolveInput defines the input parameters for the resolve_anime tool
+type ResolveInput struct {
+	Name          string `json:"name" jsonschema:"Anime name to resolve, in any of its titles or synonyms"`
+	Limit         int    `json:"limit,omitempty" jsonschema:"Number of search results to consider (1-50). Defaults to 10"`
+	TitleLanguage string `json:"title_language,omitempty" jsonschema:"Fill display_title with the title in this language (romaji, english, native), falling back to the romaji title"`
+}
+
+type ResolveOutput struct {
+	Query      string               `json:"query"`
+	Match      *mal.AnimeCandidate  `json:"match,omitempty" jsonschema:"The candidate to use, present only when one matches confidently and unambiguously"`
+	Candidates []mal.AnimeCandidate `json:"candidates" jsonschema:"Candidates ranked by confidence (0-1)"`
+}
+
+// RecommendInput defines the input parameters for the recommend_anime tool
+type RecommendInput struct {
+	User          string `json:"user,omitempty" jsonschema:"MyAnimeList user whose list to draw from. Defaults to the logged-in user"`
+	Seeds         int    `json:"seeds,omitempty" jsonschema:"Number of the user's highest-scored completed anime to draw recommendations from (1-100). Defaults to 10"`
+	Limit         int    `json:"limit,omitempty" jsonschema:"Maximum number of recommendations to return (1-100). Defaults to 10"`
+	NoSuggestions bool   `json:"no_suggestions,omitempty" jsonschema:"Leave out MyAnimeList's personalized suggestions, which are only used for the logged-in user"`
+	TitleLanguage string `json:"title_language,omitempty" jsonschema:"Fill display_title with the title in this language (romaji, english, native), falling back to the romaji title"`
+}
+
+type RecommendOutput struct {
+	Seeds           []mal.AnimeListEntry `json:"seeds" jsonschema:"The user's completed anime the recommendations were drawn from"`
+	Recommendations []mal.Recommendation `json:"recommendations" jsonschema:"Recommendations, best first, each with the anime on the user's list that led to it"`
+	Errors          []BatchDetailsError  `json:"errors,omitempty" jsonschema:"Anime whose recommendations could not be fetched, with the reason"`
+	Warnings        []string             `json:"warnings,omitempty" jsonschema:"Problems that left parts out of the result, such as MyAnimeList's suggestions being unavailable"`
 }
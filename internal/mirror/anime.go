@@ -0,0 +1,196 @@
+package mirror
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+)
+
+// AnimeService reads mirrored anime. It implements mal.AnimeSource.
+type AnimeService struct {
+	mirror *Mirror
+}
+
+var _ mal.AnimeSource = (*AnimeService)(nil)
+
+// SearchContext finds mirrored anime whose titles, English and Japanese
+// titles or synonyms contain every word of query, most popular first.
+// A limit of 0 returns every match. fields are ignored.
+func (a *AnimeService) SearchContext(ctx context.Context, query string, limit int, fields ...string) (*mal.AnimeSearchResponse, error) {
+	where, args := titleFilter(query)
+	rows, err := a.mirror.db.QueryContext(ctx,
+		`SELECT data FROM anime WHERE `+where+` ORDER BY popularity = 0, popularity, title LIMIT ?`,
+		append(args, sqlLimit(limit))...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search mirror: %w", err)
+	}
+	defer rows.Close()
+
+	results := &mal.AnimeSearchResponse{Data: []mal.AnimeData{}}
+	for rows.Next() {
+		var anime mal.AnimeDetails
+		if err := scanJSON(rows, &anime); err != nil {
+			return nil, err
+		}
+		results.Data = append(results.Data, mal.AnimeData{Node: anime})
+	}
+	return results, rows.Err()
+}
+
+// DetailsContext returns a mirrored anime. Anime that are not mirrored
+// give an error for which mal.IsNotFound is true. fields are ignored.
+// MyListStatus is filled from the list of the user who last synced their
+// own list, as MAL fills it for the logged-in user.
+func (a *AnimeService) DetailsContext(ctx context.Context, animeID int, fields ...string) (*mal.AnimeDetails, error) {
+	var anime mal.AnimeDetails
+	row := a.mirror.db.QueryRowContext(ctx, `SELECT data FROM anime WHERE id = ?`, animeID)
+	if err := scanJSON(row, &anime); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notFound("anime", animeID)
+		}
+		return nil, err
+	}
+
+	var status mal.AnimeListStatus
+	row = a.mirror.db.QueryRowContext(ctx, `
+		SELECT e.data FROM list_entries e JOIN meta ON meta.key = 'me' AND e.user_name = meta.value
+		WHERE e.type = 'anime' AND e.id = ?`, animeID)
+	switch err := scanJSON(row, &status); {
+	case err == nil:
+		anime.MyListStatus = &status
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, err
+	}
+	return &anime, nil
+}
+
+// BatchDetailsContext returns several mirrored anime, reporting the ones
+// that are not mirrored in the result's Errors
+func (a *AnimeService) BatchDetailsContext(ctx context.Context, animeIDs []int, fields ...string) (*mal.BatchResult[mal.AnimeDetails], error) {
+	return batchDetails(ctx, animeIDs, func(ctx context.Context, id int) (*mal.AnimeDetails, error) {
+		return a.DetailsContext(ctx, id)
+	})
+}
+
+var _ mal.AnimeRecorder = (*AnimeService)(nil)
+
+// RecordAnime stores anime fetched from MAL outside a sync, such as search,
+// detail and ranking results, so every anime zutto has fetched can be
+// searched with QueryContext. Fields the fetched anime lack keep their
+// mirrored values, and the mirrored updated_at is kept, so a sync still
+// fetches the full details of listed anime it has not stored itself.
+func (a *AnimeService) RecordAnime(ctx context.Context, anime []mal.AnimeDetails) error {
+	return a.mirror.write(ctx, func(tx *sql.Tx) error {
+		for i := range anime {
+			if err := mergeAnime(ctx, tx, &anime[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// mergeAnime stores the fields fetched has over those of the mirrored
+// anime with its ID, if any
+func mergeAnime(ctx context.Context, tx *sql.Tx, fetched *mal.AnimeDetails) error {
+	var merged mal.AnimeDetails
+	var updatedAt, data string
+	err := tx.QueryRowContext(ctx, `SELECT updated_at, data FROM anime WHERE id = ?`, fetched.ID).Scan(&updatedAt, &data)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal([]byte(data), &merged); err != nil {
+			return err
+		}
+	case !errors.Is(err, sql.ErrNoRows):
+		return err
+	}
+
+	// Decoding the fetched anime over the mirrored one only replaces the
+	// fields it has
+	fetchedData, err := json.Marshal(fetched)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(fetchedData, &merged); err != nil {
+		return err
+	}
+	// The list status is mirrored with the list, and display titles depend
+	// on the reader
+	merged.MyListStatus = nil
+	merged.DisplayTitle = ""
+	merged.UpdatedAt = updatedAt
+	return putAnime(ctx, tx, &merged)
+}
+
+// putAnime stores and indexes anime details. UpdatedAt is kept as MAL
+// gave it so a sync can tell when MAL has newer details.
+func putAnime(ctx context.Context, tx *sql.Tx, anime *mal.AnimeDetails) error {
+	data, err := json.Marshal(anime)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO anime (id, title, titles, popularity, updated_at, data) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET title = excluded.title, titles = excluded.titles,
+			popularity = excluded.popularity, updated_at = excluded.updated_at, data = excluded.data`,
+		anime.ID, anime.Title, searchTitles(anime.Title, anime.AlternativeTitles), anime.Popularity, anime.UpdatedAt, data)
+	if err != nil {
+		return err
+	}
+	return indexAnime(ctx, tx, anime.ID)
+}
+
+// batchDetails looks up ids one by one, like mal's batches do over the
+// network: repeated IDs are looked up once and failures are collected
+func batchDetails[T any](ctx context.Context, ids []int, get func(context.Context, int) (*T, error)) (*mal.BatchResult[T], error) {
+	result := &mal.BatchResult[T]{Results: []T{}}
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		item, err := get(ctx, id)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			result.Errors = append(result.Errors, mal.BatchError{ID: id, Err: err})
+			continue
+		}
+		result.Results = append(result.Results, *item)
+	}
+	return result, nil
+}
+
+// scanner is a *sql.Row or *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanJSON decodes the single JSON column of row into v
+func scanJSON(row scanner, v any) error {
+	var data string
+	if err := row.Scan(&data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		return fmt.Errorf("failed to read mirror: %w", err)
+	}
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		return fmt.Errorf("failed to decode mirrored data: %w", err)
+	}
+	return nil
+}
+
+// sqlLimit turns a limit where 0 means none into SQLite's -1
+func sqlLimit(limit int) int {
+	if limit <= 0 {
+		return -1
+	}
+	return limit
+}
@@ -0,0 +1,72 @@
+package mirror
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+)
+
+// newTestMirror opens an empty mirror in a temp directory holding anime
+func newTestMirror(t *testing.T, anime ...mal.AnimeDetails) *Mirror {
+	t.Helper()
+	m, err := Open(filepath.Join(t.TempDir(), "library.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { m.Close() })
+
+	ctx := context.Background()
+	err = m.write(ctx, func(tx *sql.Tx) error {
+		for i := range anime {
+			if err := putAnime(ctx, tx, &anime[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestDetailsIncludeMyListStatus(t *testing.T) {
+	m := newTestMirror(t,
+		mal.AnimeDetails{ID: 1, Title: "Cowboy Bebop"},
+		mal.AnimeDetails{ID: 2, Title: "Trigun"},
+	)
+	ctx := context.Background()
+	if err := m.setMeta(ctx, "me", "spike"); err != nil {
+		t.Fatal(err)
+	}
+	_, err := m.db.Exec(`INSERT INTO list_entries (user_name, type, id, status, score, updated_at, data) VALUES
+		('spike', 'anime', 1, 'completed', 10, '', '{"status":"completed","score":10,"num_episodes_watched":26}'),
+		('someone', 'anime', 2, 'watching', 0, '', '{"status":"watching"}')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	anime, err := m.Anime.DetailsContext(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := anime.MyListStatus; s == nil || s.Status != "completed" || s.Score != 10 || s.NumEpisodesWatched != 26 {
+		t.Errorf("MyListStatus = %+v, want the mirrored entry", s)
+	}
+
+	// Other users' lists are not the user's own
+	anime, err = m.Anime.DetailsContext(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if anime.MyListStatus != nil {
+		t.Errorf("MyListStatus = %+v from another user's list", anime.MyListStatus)
+	}
+
+	if _, err := m.Anime.DetailsContext(ctx, 3); !mal.IsNotFound(err) {
+		t.Errorf("unmirrored anime error = %v, want not found", err)
+	}
+}
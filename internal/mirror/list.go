@@ -0,0 +1,125 @@
+package mirror
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+)
+
+// ListService reads mirrored lists. It implements mal.ListSource.
+type ListService struct {
+	mirror *Mirror
+}
+
+var _ mal.ListSource = (*ListService)(nil)
+
+// animeListSorts and mangaListSorts give the ORDER BY for each MAL list
+// sort, in the direction MAL sorts them. Unsorted lists are grouped by
+// status.
+var (
+	animeListSorts = map[string]string{
+		"":                 "e.status, t.title",
+		"list_score":       "e.score DESC, t.title",
+		"list_updated_at":  "e.updated_at DESC",
+		"anime_title":      "t.title",
+		"anime_start_date": "json_extract(t.data, '$.start_date') DESC, t.title",
+	}
+	mangaListSorts = map[string]string{
+		"":                 "e.status, t.title",
+		"list_score":       "e.score DESC, t.title",
+		"list_updated_at":  "e.updated_at DESC",
+		"manga_title":      "t.title",
+		"manga_start_date": "json_extract(t.data, '$.start_date') DESC, t.title",
+	}
+)
+
+// AnimeListContext returns a mirrored anime list. Use "@me" for the user
+// who ran the last sync of their own list. Unlike MAL, a zero Limit returns
+// the whole list. Lists that have not been synced give ErrNotSynced.
+func (l *ListService) AnimeListContext(ctx context.Context, userName string, opts mal.AnimeListOptions) (*mal.AnimeListResponse, error) {
+	list := &mal.AnimeListResponse{Data: []mal.AnimeListEntry{}}
+	err := l.query(ctx, userName, "anime", animeListSorts, opts.Status, opts.Sort, opts.Limit, opts.Offset, func(status, node []byte) error {
+		var entry mal.AnimeListEntry
+		if err := json.Unmarshal(status, &entry.ListStatus); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(node, &entry.Node); err != nil {
+			return err
+		}
+		list.Data = append(list.Data, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// MangaListContext returns a mirrored manga list like AnimeListContext
+func (l *ListService) MangaListContext(ctx context.Context, userName string, opts mal.MangaListOptions) (*mal.MangaListResponse, error) {
+	list := &mal.MangaListResponse{Data: []mal.MangaListEntry{}}
+	err := l.query(ctx, userName, "manga", mangaListSorts, opts.Status, opts.Sort, opts.Limit, opts.Offset, func(status, node []byte) error {
+		var entry mal.MangaListEntry
+		if err := json.Unmarshal(status, &entry.ListStatus); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(node, &entry.Node); err != nil {
+			return err
+		}
+		list.Data = append(list.Data, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// query reads the entries of one list, passing each entry's list status
+// and title details as JSON to add
+func (l *ListService) query(ctx context.Context, userName, kind string, sorts map[string]string, status, sort string, limit, offset int, add func(status, node []byte) error) error {
+	orderBy, ok := sorts[sort]
+	if !ok {
+		return fmt.Errorf("invalid list sort: %s", sort)
+	}
+	user, err := l.mirror.userKey(ctx, userName)
+	if err != nil {
+		return fmt.Errorf("%s's %s list: %w", userName, kind, err)
+	}
+	synced, err := l.mirror.syncedAt(ctx, user, kind)
+	if err != nil {
+		return err
+	}
+	if synced == "" {
+		return fmt.Errorf("%s's %s list: %w", userName, kind, ErrNotSynced)
+	}
+
+	// kind is "anime" or "manga", both table names
+	query := `SELECT e.data, t.data FROM list_entries e JOIN ` + kind + ` t ON t.id = e.id
+		WHERE e.user_name = ? AND e.type = ?`
+	args := []any{user, kind}
+	if status != "" {
+		query += ` AND e.status = ?`
+		args = append(args, status)
+	}
+	query += ` ORDER BY ` + orderBy + ` LIMIT ? OFFSET ?`
+	args = append(args, sqlLimit(limit), max(offset, 0))
+
+	rows, err := l.mirror.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to read mirror: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var status, node []byte
+		if err := rows.Scan(&status, &node); err != nil {
+			return fmt.Errorf("failed to read mirror: %w", err)
+		}
+		if err := add(status, node); err != nil {
+			return fmt.Errorf("failed to decode mirrored data: %w", err)
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,79 @@
+package mirror
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+)
+
+// MangaService reads mirrored manga. It implements mal.MangaSource.
+type MangaService struct {
+	mirror *Mirror
+}
+
+var _ mal.MangaSource = (*MangaService)(nil)
+
+// SearchContext finds mirrored manga like AnimeService.SearchContext does
+// for anime
+func (m *MangaService) SearchContext(ctx context.Context, query string, limit int) (*mal.MangaSearchResponse, error) {
+	where, args := titleFilter(query)
+	rows, err := m.mirror.db.QueryContext(ctx,
+		`SELECT data FROM manga WHERE `+where+` ORDER BY popularity = 0, popularity, title LIMIT ?`,
+		append(args, sqlLimit(limit))...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search mirror: %w", err)
+	}
+	defer rows.Close()
+
+	results := &mal.MangaSearchResponse{Data: []mal.MangaData{}}
+	for rows.Next() {
+		var manga mal.MangaDetails
+		if err := scanJSON(rows, &manga); err != nil {
+			return nil, err
+		}
+		results.Data = append(results.Data, mal.MangaData{Node: mal.MangaNode{
+			ID:                manga.ID,
+			Title:             manga.Title,
+			AlternativeTitles: manga.AlternativeTitles,
+		}})
+	}
+	return results, rows.Err()
+}
+
+// DetailsContext returns a mirrored manga. Manga that are not mirrored
+// give an error for which mal.IsNotFound is true.
+func (m *MangaService) DetailsContext(ctx context.Context, mangaID int) (*mal.MangaDetails, error) {
+	var manga mal.MangaDetails
+	row := m.mirror.db.QueryRowContext(ctx, `SELECT data FROM manga WHERE id = ?`, mangaID)
+	if err := scanJSON(row, &manga); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notFound("manga", mangaID)
+		}
+		return nil, err
+	}
+	return &manga, nil
+}
+
+// BatchDetailsContext returns several mirrored manga, reporting the ones
+// that are not mirrored in the result's Errors
+func (m *MangaService) BatchDetailsContext(ctx context.Context, mangaIDs []int) (*mal.BatchResult[mal.MangaDetails], error) {
+	return batchDetails(ctx, mangaIDs, m.DetailsContext)
+}
+
+// putManga stores manga details like putAnime does for anime
+func putManga(ctx context.Context, tx *sql.Tx, manga *mal.MangaDetails) error {
+	data, err := json.Marshal(manga)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO manga (id, title, titles, popularity, updated_at, data) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET title = excluded.title, titles = excluded.titles,
+			popularity = excluded.popularity, updated_at = excluded.updated_at, data = excluded.data`,
+		manga.ID, manga.Title, searchTitles(manga.Title, manga.AlternativeTitles), manga.Popularity, manga.UpdatedAt, data)
+	return err
+}
@@ -0,0 +1,174 @@
+// Package mirror keeps a local copy of users' anime and manga lists and of
+// the details of every listed title in a SQLite database, so they can be
+// read without the network. Anime fetched outside a sync can be recorded
+// too, and every mirrored anime is indexed for full-text search.
+//
+// Mirror's services implement the mal.AnimeSource, mal.MangaSource and
+// mal.ListSource interfaces, so code written against them reads from MAL
+// or from the mirror alike. Details are stored as MAL returned them and
+// are returned whole, whatever fields are asked for.
+package mirror
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+	_ "modernc.org/sqlite"
+)
+
+// ErrNotSynced is returned when reading a list that has not been synced
+var ErrNotSynced = errors.New("list has not been synced")
+
+const schema = `
+CREATE TABLE IF NOT EXISTS anime (
+	id         INTEGER PRIMARY KEY,
+	title      TEXT NOT NULL,
+	titles     TEXT NOT NULL,
+	popularity INTEGER NOT NULL DEFAULT 0,
+	updated_at TEXT NOT NULL,
+	data       TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS manga (
+	id         INTEGER PRIMARY KEY,
+	title      TEXT NOT NULL,
+	titles     TEXT NOT NULL,
+	popularity INTEGER NOT NULL DEFAULT 0,
+	updated_at TEXT NOT NULL,
+	data       TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS list_entries (
+	user_name  TEXT NOT NULL,
+	type       TEXT NOT NULL,
+	id         INTEGER NOT NULL,
+	status     TEXT NOT NULL,
+	score      INTEGER NOT NULL,
+	updated_at TEXT NOT NULL,
+	data       TEXT NOT NULL,
+	PRIMARY KEY (user_name, type, id)
+);
+CREATE TABLE IF NOT EXISTS syncs (
+	user_name TEXT NOT NULL,
+	type      TEXT NOT NULL,
+	synced_at TEXT NOT NULL,
+	PRIMARY KEY (user_name, type)
+);
+CREATE TABLE IF NOT EXISTS meta (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+`
+
+// Mirror is a local copy of lists and title details
+type Mirror struct {
+	db *sql.DB
+
+	Anime *AnimeService
+	Manga *MangaService
+	List  *ListService
+}
+
+// DefaultPath returns where the mirror is kept, next to the response cache
+// in the user's cache directory
+func DefaultPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate cache directory: %w", err)
+	}
+	return filepath.Join(dir, "zutto", "library.db"), nil
+}
+
+// Open opens the mirror at path, creating it if needed
+func Open(path string) (*Mirror, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create mirror directory: %w", err)
+	}
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mirror: %w", err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open mirror: %w", err)
+	}
+
+	m := &Mirror{db: db}
+	if err := m.createSearchIndex(context.Background()); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open mirror: %w", err)
+	}
+	m.Anime = &AnimeService{mirror: m}
+	m.Manga = &MangaService{mirror: m}
+	m.List = &ListService{mirror: m}
+	return m, nil
+}
+
+// Close closes the database
+func (m *Mirror) Close() error {
+	return m.db.Close()
+}
+
+// me is the stored name of the logged-in user, set by syncing "@me"
+func (m *Mirror) me(ctx context.Context) (string, error) {
+	var name string
+	err := m.db.QueryRowContext(ctx, `SELECT value FROM meta WHERE key = 'me'`).Scan(&name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrNotSynced
+	}
+	return name, err
+}
+
+// userKey turns a user name as given to a list method into the stored
+// name; MAL user names are not case sensitive
+func (m *Mirror) userKey(ctx context.Context, userName string) (string, error) {
+	if userName == "@me" {
+		return m.me(ctx)
+	}
+	return strings.ToLower(userName), nil
+}
+
+// notFound is returned for titles missing from the mirror, so
+// mal.IsNotFound treats them like unknown IDs on MAL
+func notFound(kind string, id int) error {
+	return &mal.APIError{
+		StatusCode: http.StatusNotFound,
+		Err:        "not_found",
+		Message:    fmt.Sprintf("%s %d is not in the local mirror", kind, id),
+	}
+}
+
+// searchTitles joins a title's names, lowercased, for searching
+func searchTitles(title string, alt mal.AlternativeTitles) string {
+	titles := []string{title, alt.En, alt.Ja}
+	if alt.Synonyms != nil {
+		titles = append(titles, *alt.Synonyms...)
+	}
+	return strings.ToLower(strings.Join(titles, "\n"))
+}
+
+// likePattern matches values containing word in a LIKE ... ESCAPE '\'
+func likePattern(word string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + r.Replace(word) + "%"
+}
+
+// titleFilter returns a WHERE clause matching every word of query in the
+// titles column, with its arguments
+func titleFilter(query string) (string, []any) {
+	var clauses []string
+	var args []any
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		clauses = append(clauses, `titles LIKE ? ESCAPE '\'`)
+		args = append(args, likePattern(word))
+	}
+	if len(clauses) == 0 {
+		return "1", nil
+	}
+	return strings.Join(clauses, " AND "), args
+}
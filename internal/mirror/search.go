@@ -0,0 +1,308 @@
+package mirror
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+)
+
+// searchSchema is the full-text index over mirrored anime. Its rowid is
+// the anime ID; titles holds every name from the anime table's titles.
+const searchSchema = `
+CREATE VIRTUAL TABLE IF NOT EXISTS anime_search USING fts5(
+	title, titles, synopsis,
+	tokenize = 'unicode61 remove_diacritics 2'
+);
+`
+
+// indexAnime brings the search index entry for one stored anime up to date
+func indexAnime(ctx context.Context, tx *sql.Tx, animeID int) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM anime_search WHERE rowid = ?`, animeID); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO anime_search (rowid, title, titles, synopsis)
+		SELECT id, title, titles, coalesce(json_extract(data, '$.synopsis'), '') FROM anime WHERE id = ?`, animeID)
+	return err
+}
+
+// createSearchIndex adds the search index to a mirror made before it
+// existed, indexing the anime already stored
+func (m *Mirror) createSearchIndex(ctx context.Context) error {
+	var exists bool
+	err := m.db.QueryRowContext(ctx,
+		`SELECT count(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = 'anime_search'`).Scan(&exists)
+	if err != nil || exists {
+		return err
+	}
+	return m.write(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, searchSchema); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO anime_search (rowid, title, titles, synopsis)
+			SELECT id, title, titles, coalesce(json_extract(data, '$.synopsis'), '') FROM anime`)
+		return err
+	})
+}
+
+// QueryContext runs a full-text query over every mirrored anime, those on
+// synced lists and those recorded by RecordAnime, best match first. Unlike
+// SearchContext it also searches synopses and takes a small query language:
+//
+//	frieren            titles or synopsis contain the word
+//	frie*              a word starts with "frie"
+//	"steins gate"      the words appear together, in order
+//	title:gate         only titles are searched (synopsis: for synopses)
+//	genre:mecha        a genre name contains "mecha" (also studio:)
+//	type:tv            the media type is tv (also status: and season:)
+//	year:>2015         the start year is after 2015; year:2015,
+//	                   year:<=2015 and year:2010..2015 work too, as do
+//	                   score: and episodes:
+//	-word, -genre:x    matches are excluded
+//
+// Terms are combined with AND. Values with spaces are quoted, as in
+// genre:"slice of life". A word with a colon that is not a field, like
+// re:zero, is searched as text. A limit of 0 returns every match.
+func (a *AnimeService) QueryContext(ctx context.Context, query string, limit int) (*mal.AnimeSearchResponse, error) {
+	q, err := parseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlQuery := `SELECT a.data FROM anime a`
+	var args []any
+	orderBy := `a.popularity = 0, a.popularity, a.title`
+	if match := q.match(); match != "" {
+		sqlQuery += ` JOIN anime_search ON anime_search.rowid = a.id AND anime_search MATCH ?`
+		args = append(args, match)
+		// Titles count for more than the synopsis
+		orderBy = `bm25(anime_search, 10, 5, 1), ` + orderBy
+	}
+	where := []string{"1"}
+	for _, f := range q.filters {
+		where = append(where, f.sql)
+		args = append(args, f.args...)
+	}
+	sqlQuery += ` WHERE ` + strings.Join(where, " AND ") + ` ORDER BY ` + orderBy + ` LIMIT ?`
+	args = append(args, sqlLimit(limit))
+
+	rows, err := a.mirror.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search mirror: %w", err)
+	}
+	defer rows.Close()
+
+	results := &mal.AnimeSearchResponse{Data: []mal.AnimeData{}}
+	for rows.Next() {
+		var anime mal.AnimeDetails
+		if err := scanJSON(rows, &anime); err != nil {
+			return nil, err
+		}
+		results.Data = append(results.Data, mal.AnimeData{Node: anime})
+	}
+	return results, rows.Err()
+}
+
+// textQuery is a parsed QueryContext query
+type textQuery struct {
+	terms   []string // FTS5 expressions that must match
+	filters []filter
+}
+
+// filter is a WHERE condition on the anime table, aliased a
+type filter struct {
+	sql  string
+	args []any
+}
+
+// match returns the FTS5 expression for q's text terms, or "" if it has none
+func (q *textQuery) match() string {
+	return strings.Join(q.terms, " AND ")
+}
+
+// numericFields and textFields give the SQL for each query field
+var (
+	numericFields = map[string]string{
+		"year":     `CAST(substr(json_extract(a.data, '$.start_date'), 1, 4) AS INTEGER)`,
+		"score":    `json_extract(a.data, '$.mean')`,
+		"episodes": `json_extract(a.data, '$.num_episodes')`,
+	}
+	// textFields compare a value exactly, ignoring case
+	textFields = map[string]string{
+		"type":   `json_extract(a.data, '$.media_type')`,
+		"status": `json_extract(a.data, '$.status')`,
+		"season": `json_extract(a.data, '$.start_season.season')`,
+	}
+	// nameFields match a value within any name of a list
+	nameFields = map[string]string{
+		"genre":  `$.genres`,
+		"studio": `$.studios`,
+	}
+	// columnFields search the text of some index columns
+	columnFields = map[string]string{
+		"title":    `{title titles}`,
+		"synopsis": `synopsis`,
+	}
+)
+
+// parseQuery parses a QueryContext query
+func parseQuery(query string) (*textQuery, error) {
+	tokens, err := splitQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	q := &textQuery{}
+	for _, token := range tokens {
+		negate := len(token) > 1 && token[0] == '-'
+		if negate {
+			token = token[1:]
+		}
+
+		var term string
+		if name, value, ok := strings.Cut(token, ":"); ok && isField(strings.ToLower(name)) {
+			name = strings.ToLower(name)
+			if value = unquote(value); value == "" {
+				return nil, fmt.Errorf("invalid query: %s: needs a value", name)
+			}
+			if column, ok := columnFields[name]; ok {
+				if term = ftsTerm(value); term == "" {
+					continue
+				}
+				term = column + " : " + term
+			} else {
+				f, err := fieldFilter(name, value)
+				if err != nil {
+					return nil, err
+				}
+				if negate {
+					f.sql = "NOT coalesce(" + f.sql + ", 0)"
+				}
+				q.filters = append(q.filters, f)
+				continue
+			}
+		} else if term = ftsTerm(token); term == "" {
+			continue
+		}
+
+		if negate {
+			q.filters = append(q.filters, filter{
+				sql:  `a.id NOT IN (SELECT rowid FROM anime_search WHERE anime_search MATCH ?)`,
+				args: []any{term},
+			})
+			continue
+		}
+		q.terms = append(q.terms, term)
+	}
+	return q, nil
+}
+
+func isField(name string) bool {
+	_, numeric := numericFields[name]
+	_, text := textFields[name]
+	_, names := nameFields[name]
+	_, column := columnFields[name]
+	return numeric || text || names || column
+}
+
+// fieldFilter returns the filter for a field other than a column field
+func fieldFilter(name, value string) (filter, error) {
+	if path, ok := nameFields[name]; ok {
+		return filter{
+			sql: `EXISTS (SELECT 1 FROM json_each(a.data, '` + path + `') j
+				WHERE json_extract(j.value, '$.name') LIKE ? ESCAPE '\')`,
+			args: []any{likePattern(value)},
+		}, nil
+	}
+	if expr, ok := textFields[name]; ok {
+		return filter{sql: `lower(` + expr + `) = ?`, args: []any{strings.ToLower(value)}}, nil
+	}
+
+	expr := numericFields[name]
+	if lo, hi, ok := strings.Cut(value, ".."); ok {
+		low, err := parseNumber(name, lo)
+		if err != nil {
+			return filter{}, err
+		}
+		high, err := parseNumber(name, hi)
+		if err != nil {
+			return filter{}, err
+		}
+		return filter{sql: expr + ` BETWEEN ? AND ?`, args: []any{low, high}}, nil
+	}
+	op := "="
+	for _, prefix := range []string{">=", "<=", ">", "<", "="} {
+		if rest, ok := strings.CutPrefix(value, prefix); ok {
+			op, value = prefix, rest
+			break
+		}
+	}
+	n, err := parseNumber(name, value)
+	if err != nil {
+		return filter{}, err
+	}
+	return filter{sql: expr + ` ` + op + ` ?`, args: []any{n}}, nil
+}
+
+func parseNumber(name, value string) (float64, error) {
+	n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid query: %s: %q is not a number", name, value)
+	}
+	return n, nil
+}
+
+// ftsTerm turns a word or quoted phrase, either ending in * for a prefix
+// match, into an FTS5 string. Text without letters or digits gives "".
+func ftsTerm(text string) string {
+	prefix := strings.HasSuffix(text, "*")
+	text = unquote(strings.TrimSuffix(text, "*"))
+	if !strings.ContainsFunc(text, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) {
+		return ""
+	}
+	term := `"` + strings.ReplaceAll(text, `"`, `""`) + `"`
+	if prefix {
+		term += "*"
+	}
+	return term
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// splitQuery splits a query at spaces outside double quotes
+func splitQuery(query string) ([]string, error) {
+	var tokens []string
+	var token strings.Builder
+	quoted := false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			token.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if token.Len() > 0 {
+				tokens = append(tokens, token.String())
+				token.Reset()
+			}
+		default:
+			token.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("invalid query: unterminated quote")
+	}
+	if token.Len() > 0 {
+		tokens = append(tokens, token.String())
+	}
+	return tokens, nil
+}
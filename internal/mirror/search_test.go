@@ -0,0 +1,271 @@
+package mirror
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+)
+
+func TestSplitQuery(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    []string
+		wantErr bool
+	}{
+		{"frieren", []string{"frieren"}, false},
+		{"  steins   gate ", []string{"steins", "gate"}, false},
+		{`"steins gate" -type:movie`, []string{`"steins gate"`, "-type:movie"}, false},
+		{`genre:"slice of life" year:>2015`, []string{`genre:"slice of life"`, "year:>2015"}, false},
+		{"", nil, false},
+		{`"steins gate`, nil, true},
+		{`genre:"slice of life`, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := splitQuery(tt.query)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("splitQuery(%q) = %q, want an error", tt.query, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("splitQuery(%q): %v", tt.query, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitQuery(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestFieldFilter(t *testing.T) {
+	tests := []struct {
+		name, value string
+		// wantSQL is a fragment the filter's SQL must contain
+		wantSQL  string
+		wantArgs []any
+		wantErr  bool
+	}{
+		{"year", "2010..2015", numericFields["year"] + " BETWEEN ? AND ?", []any{2010.0, 2015.0}, false},
+		{"score", ">=8", numericFields["score"] + " >= ?", []any{8.0}, false},
+		{"score", "8.5", numericFields["score"] + " = ?", []any{8.5}, false},
+		{"year", ">2015", numericFields["year"] + " > ?", []any{2015.0}, false},
+		{"episodes", "<=13", numericFields["episodes"] + " <= ?", []any{13.0}, false},
+		{"type", "TV", "lower(" + textFields["type"] + ") = ?", []any{"tv"}, false},
+		{"genre", "slice of life", "LIKE ?", []any{"%slice of life%"}, false},
+		{"studio", "100%", "LIKE ?", []any{`%100\%%`}, false},
+		{"score", "high", "", nil, true},
+		{"year", "2010..", "", nil, true},
+		{"year", "..2015", "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+":"+tt.value, func(t *testing.T) {
+			f, err := fieldFilter(tt.name, tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("got %q, want an error", f.sql)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(f.sql, tt.wantSQL) {
+				t.Errorf("SQL %q does not contain %q", f.sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(f.args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", f.args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		query     string
+		wantTerms []string
+		// wantFilters are fragments of each filter's SQL, in order
+		wantFilters []string
+		wantArgs    [][]any
+	}{
+		{"frieren", []string{`"frieren"`}, nil, nil},
+		{"frie*", []string{`"frie"*`}, nil, nil},
+		{`"steins gate"`, []string{`"steins gate"`}, nil, nil},
+		{"title:gate", []string{`{title titles} : "gate"`}, nil, nil},
+		{"Synopsis:time*", []string{`synopsis : "time"*`}, nil, nil},
+		{
+			"year:2010..2015",
+			nil,
+			[]string{"BETWEEN ? AND ?"},
+			[][]any{{2010.0, 2015.0}},
+		},
+		{
+			"mecha score:>=8",
+			[]string{`"mecha"`},
+			[]string{">= ?"},
+			[][]any{{8.0}},
+		},
+		{
+			`genre:"slice of life"`,
+			nil,
+			[]string{"LIKE ?"},
+			[][]any{{"%slice of life%"}},
+		},
+		{
+			"-genre:x",
+			nil,
+			[]string{"NOT coalesce(EXISTS"},
+			[][]any{{"%x%"}},
+		},
+		{
+			"gundam -word",
+			[]string{`"gundam"`},
+			[]string{"a.id NOT IN"},
+			[][]any{{`"word"`}},
+		},
+		{"re:zero", []string{`"re:zero"`}, nil, nil},
+		// A lone - and punctuation are not terms
+		{"- ... gate", []string{`"gate"`}, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := parseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(q.terms, tt.wantTerms) {
+				t.Errorf("terms = %q, want %q", q.terms, tt.wantTerms)
+			}
+			if len(q.filters) != len(tt.wantFilters) {
+				t.Fatalf("got %d filters, want %d: %+v", len(q.filters), len(tt.wantFilters), q.filters)
+			}
+			for i, f := range q.filters {
+				if !strings.Contains(f.sql, tt.wantFilters[i]) {
+					t.Errorf("filter %d SQL %q does not contain %q", i, f.sql, tt.wantFilters[i])
+				}
+				if !reflect.DeepEqual(f.args, tt.wantArgs[i]) {
+					t.Errorf("filter %d args = %v, want %v", i, f.args, tt.wantArgs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for _, query := range []string{
+		`"steins gate`,
+		"genre:",
+		`studio:""`,
+		"score:high",
+	} {
+		if _, err := parseQuery(query); err == nil {
+			t.Errorf("parseQuery(%q) succeeded, want an error", query)
+		}
+	}
+}
+
+func TestQuery(t *testing.T) {
+	m := newTestMirror(t,
+		mal.AnimeDetails{
+			ID: 1, Title: "Cowboy Bebop", Popularity: 40, StartDate: "1998-04-03", Mean: 8.75, MediaType: "tv",
+			Genres:   []mal.Genre{{Name: "Action"}, {Name: "Sci-Fi"}},
+			Synopsis: "Bounty hunters travel through space.",
+		},
+		mal.AnimeDetails{
+			ID: 5, Title: "Cowboy Bebop: Tengoku no Tobira", Popularity: 600, StartDate: "2001-09-01", Mean: 8.38, MediaType: "movie",
+			Genres: []mal.Genre{{Name: "Action"}},
+		},
+		mal.AnimeDetails{
+			ID: 9253, Title: "Steins;Gate", Popularity: 13, StartDate: "2011-04-06", Mean: 9.07, MediaType: "tv",
+			Genres:   []mal.Genre{{Name: "Sci-Fi"}, {Name: "Suspense"}},
+			Synopsis: "A self-proclaimed mad scientist discovers time travel.",
+		},
+	)
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"cowboy", []int{1, 5}},
+		{"bebo*", []int{1, 5}},
+		{`"time travel"`, []int{9253}},
+		{"title:travel", nil},
+		{"genre:sci-fi", []int{9253, 1}},
+		{"cowboy -type:movie", []int{1}},
+		{"year:1998..2005", []int{1, 5}},
+		{"score:>=8.5 -genre:suspense", []int{1}},
+		{"-bebop", []int{9253}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results, err := m.Anime.QueryContext(context.Background(), tt.query, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []int
+			for _, d := range results.Data {
+				ids = append(ids, d.Node.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("QueryContext(%q) = %v, want %v", tt.query, ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordAnime(t *testing.T) {
+	m := newTestMirror(t, mal.AnimeDetails{
+		ID: 1, Title: "Cowboy Bebop", Popularity: 40, UpdatedAt: "2024-01-01T00:00:00+00:00",
+		Synopsis: "Bounty hunters travel through space.",
+		Studios:  []mal.Studio{{Name: "Sunrise"}},
+	})
+	ctx := context.Background()
+
+	// A search result has fewer fields than the synced details, and a
+	// newer mean
+	err := m.Anime.RecordAnime(ctx, []mal.AnimeDetails{
+		{ID: 1, Title: "Cowboy Bebop", Mean: 8.75, MyListStatus: &mal.AnimeListStatus{Status: "watching"}},
+		{ID: 9253, Title: "Steins;Gate", AlternativeTitles: mal.AlternativeTitles{En: "Steins;Gate"}, Popularity: 13},
+	})
+	if err != nil {
+		t.Fatalf("RecordAnime: %v", err)
+	}
+
+	anime, err := m.Anime.DetailsContext(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if anime.Synopsis == "" || len(anime.Studios) != 1 || anime.Popularity != 40 {
+		t.Errorf("recording a search result lost synced details: %+v", anime)
+	}
+	if anime.Mean != 8.75 {
+		t.Errorf("mean = %v, want the recorded 8.75", anime.Mean)
+	}
+	if anime.MyListStatus != nil {
+		t.Errorf("MyListStatus = %+v, want it taken from the mirrored list only", anime.MyListStatus)
+	}
+
+	// Sync still sees the synced details as current, and the recorded
+	// anime as lacking details
+	updated, err := m.updatedAts(ctx, `SELECT id, updated_at FROM anime`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[int]string{1: "2024-01-01T00:00:00+00:00", 9253: ""}
+	if !reflect.DeepEqual(updated, want) {
+		t.Errorf("updated_at = %v, want %v", updated, want)
+	}
+
+	for query, wantID := range map[string]int{"steins": 9253, "studio:sunrise": 1, `"bounty hunters"`: 1} {
+		results, err := m.Anime.QueryContext(ctx, query, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results.Data) != 1 || results.Data[0].Node.ID != wantID {
+			t.Errorf("QueryContext(%q) = %+v, want anime %d", query, results.Data, wantID)
+		}
+	}
+}
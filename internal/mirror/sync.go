@@ -0,0 +1,355 @@
+package mirror
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+)
+
+// detailsBatchSize is how many titles' details are fetched and stored at a
+// time, so an interrupted sync keeps most of its work
+const detailsBatchSize = 50
+
+// SyncOptions selects what Sync mirrors
+type SyncOptions struct {
+	// User is the user whose lists are mirrored; "@me" when empty
+	User string
+	// Anime and Manga select the lists to mirror
+	Anime bool
+	Manga bool
+	// Full fetches the details of every listed title again, rather than
+	// only those MAL has updated since they were mirrored
+	Full bool
+	// Progress is called, if set, after each batch of details is stored
+	Progress func(kind string, done, total int)
+}
+
+// SyncStats counts what a sync changed in one list
+type SyncStats struct {
+	Entries        int              `json:"entries"`
+	Added          int              `json:"added"`
+	Updated        int              `json:"updated"`
+	Removed        int              `json:"removed"`
+	DetailsFetched int              `json:"details_fetched"`
+	Errors         []mal.BatchError `json:"errors,omitempty"`
+}
+
+// SyncResult reports a sync
+type SyncResult struct {
+	User  string     `json:"user"`
+	Anime *SyncStats `json:"anime,omitempty"`
+	Manga *SyncStats `json:"manga,omitempty"`
+}
+
+// syncedEntry is a fetched list entry reduced to what is stored
+type syncedEntry struct {
+	id        int
+	status    string
+	score     int
+	updatedAt string
+	data      []byte
+	// nodeUpdatedAt is when MAL last changed the title itself
+	nodeUpdatedAt string
+}
+
+// Sync copies users' lists from MAL into the mirror, along with the
+// details of every listed title. It is incremental: list entries are only
+// rewritten when their updated_at changed, entries gone from MAL are
+// removed, and details are only fetched for titles that are new to the
+// mirror or that MAL has updated since.
+//
+// Details that fail to fetch are reported in the stats' Errors and tried
+// again by the next sync. The client should not serve details from its
+// response cache, or changes MAL made since they were cached go unnoticed.
+func (m *Mirror) Sync(ctx context.Context, client *mal.Client, opts SyncOptions) (*SyncResult, error) {
+	userName := opts.User
+	if userName == "" || userName == "@me" {
+		me, err := client.User.MeContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		userName = me.Name
+		if err := m.setMeta(ctx, "me", strings.ToLower(userName)); err != nil {
+			return nil, err
+		}
+	}
+	result := &SyncResult{User: userName}
+
+	if opts.Anime {
+		stats, err := m.syncAnime(ctx, client, userName, opts)
+		if err != nil {
+			return nil, err
+		}
+		result.Anime = stats
+	}
+	if opts.Manga {
+		stats, err := m.syncManga(ctx, client, userName, opts)
+		if err != nil {
+			return nil, err
+		}
+		result.Manga = stats
+	}
+	return result, nil
+}
+
+func (m *Mirror) syncAnime(ctx context.Context, client *mal.Client, userName string, opts SyncOptions) (*SyncStats, error) {
+	listOpts := mal.AnimeListOptions{Limit: 1000, Fields: []string{mal.AnimeListStatusFields, "updated_at", "popularity"}}
+	var entries []syncedEntry
+	var nodes []mal.AnimeDetails
+	for entry, err := range client.List.AnimeListAllContext(ctx, userName, listOpts, 0) {
+		if err != nil {
+			return nil, err
+		}
+		synced, err := newSyncedEntry(entry.Node.ID, entry.ListStatus.Status, entry.ListStatus.Score, entry.ListStatus.UpdatedAt, entry.Node.UpdatedAt, entry.ListStatus)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, synced)
+		nodes = append(nodes, entry.Node)
+	}
+
+	fetch := func(ctx context.Context, ids []int) ([]int, []mal.BatchError, error) {
+		batch, err := client.Anime.BatchDetailsContext(ctx, ids, animeDetailFields()...)
+		if err != nil {
+			return nil, nil, err
+		}
+		err = m.write(ctx, func(tx *sql.Tx) error {
+			for i := range batch.Results {
+				if err := putAnime(ctx, tx, &batch.Results[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		return idsOf(batch.Results, func(a mal.AnimeDetails) int { return a.ID }), batch.Errors, err
+	}
+	putNode := func(ctx context.Context, tx *sql.Tx, i int) error {
+		// Stored without updated_at so the details are fetched
+		node := nodes[i]
+		node.UpdatedAt = ""
+		return putAnime(ctx, tx, &node)
+	}
+	return m.syncList(ctx, userName, "anime", entries, putNode, fetch, opts)
+}
+
+func (m *Mirror) syncManga(ctx context.Context, client *mal.Client, userName string, opts SyncOptions) (*SyncStats, error) {
+	listOpts := mal.MangaListOptions{Limit: 1000, Fields: []string{mal.MangaListStatusFields, "updated_at", "popularity"}}
+	var entries []syncedEntry
+	var nodes []mal.MangaDetails
+	for entry, err := range client.List.MangaListAllContext(ctx, userName, listOpts, 0) {
+		if err != nil {
+			return nil, err
+		}
+		synced, err := newSyncedEntry(entry.Node.ID, entry.ListStatus.Status, entry.ListStatus.Score, entry.ListStatus.UpdatedAt, entry.Node.UpdatedAt, entry.ListStatus)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, synced)
+		nodes = append(nodes, entry.Node)
+	}
+
+	fetch := func(ctx context.Context, ids []int) ([]int, []mal.BatchError, error) {
+		batch, err := client.Manga.BatchDetailsContext(ctx, ids)
+		if err != nil {
+			return nil, nil, err
+		}
+		err = m.write(ctx, func(tx *sql.Tx) error {
+			for i := range batch.Results {
+				if err := putManga(ctx, tx, &batch.Results[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		return idsOf(batch.Results, func(manga mal.MangaDetails) int { return manga.ID }), batch.Errors, err
+	}
+	putNode := func(ctx context.Context, tx *sql.Tx, i int) error {
+		node := nodes[i]
+		node.UpdatedAt = ""
+		return putManga(ctx, tx, &node)
+	}
+	return m.syncList(ctx, userName, "manga", entries, putNode, fetch, opts)
+}
+
+func newSyncedEntry(id int, status string, score int, updatedAt, nodeUpdatedAt string, listStatus any) (syncedEntry, error) {
+	data, err := json.Marshal(listStatus)
+	if err != nil {
+		return syncedEntry{}, err
+	}
+	return syncedEntry{id: id, status: status, score: score, updatedAt: updatedAt, data: data, nodeUpdatedAt: nodeUpdatedAt}, nil
+}
+
+// syncList stores a fetched list of kind ("anime" or "manga") and fetches
+// the details that are missing or out of date. putNode stores the short
+// title entries[i] came with, for titles not mirrored yet; fetch fetches
+// and stores details, returning the IDs it stored.
+func (m *Mirror) syncList(
+	ctx context.Context, userName, kind string, entries []syncedEntry,
+	putNode func(ctx context.Context, tx *sql.Tx, i int) error,
+	fetch func(ctx context.Context, ids []int) ([]int, []mal.BatchError, error),
+	opts SyncOptions,
+) (*SyncStats, error) {
+	user := strings.ToLower(userName)
+	stats := &SyncStats{Entries: len(entries)}
+
+	stored, err := m.updatedAts(ctx, `SELECT id, updated_at FROM list_entries WHERE user_name = ? AND type = ?`, user, kind)
+	if err != nil {
+		return nil, err
+	}
+	details, err := m.updatedAts(ctx, `SELECT id, updated_at FROM `+kind)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.write(ctx, func(tx *sql.Tx) error {
+		listed := make(map[int]bool, len(entries))
+		for i, e := range entries {
+			listed[e.id] = true
+			if _, ok := details[e.id]; !ok {
+				if err := putNode(ctx, tx, i); err != nil {
+					return err
+				}
+				details[e.id] = ""
+			}
+
+			updatedAt, ok := stored[e.id]
+			switch {
+			case !ok:
+				stats.Added++
+			case updatedAt != e.updatedAt:
+				stats.Updated++
+			default:
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO list_entries (user_name, type, id, status, score, updated_at, data) VALUES (?, ?, ?, ?, ?, ?, ?)
+				ON CONFLICT (user_name, type, id) DO UPDATE SET status = excluded.status, score = excluded.score,
+					updated_at = excluded.updated_at, data = excluded.data`,
+				user, kind, e.id, e.status, e.score, e.updatedAt, e.data); err != nil {
+				return err
+			}
+		}
+		for id := range stored {
+			if !listed[id] {
+				if _, err := tx.ExecContext(ctx, `DELETE FROM list_entries WHERE user_name = ? AND type = ? AND id = ?`, user, kind, id); err != nil {
+					return err
+				}
+				stats.Removed++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var stale []int
+	for _, e := range entries {
+		if updatedAt := details[e.id]; opts.Full || updatedAt == "" || updatedAt != e.nodeUpdatedAt {
+			stale = append(stale, e.id)
+		}
+	}
+	for start := 0; start < len(stale); start += detailsBatchSize {
+		ids, errs, err := fetch(ctx, stale[start:min(start+detailsBatchSize, len(stale))])
+		if err != nil {
+			return nil, err
+		}
+		stats.DetailsFetched += len(ids)
+		stats.Errors = append(stats.Errors, errs...)
+		if opts.Progress != nil {
+			opts.Progress(kind, min(start+detailsBatchSize, len(stale)), len(stale))
+		}
+	}
+
+	if err := m.write(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO syncs (user_name, type, synced_at) VALUES (?, ?, ?)
+			ON CONFLICT (user_name, type) DO UPDATE SET synced_at = excluded.synced_at`,
+			user, kind, time.Now().UTC().Format(time.RFC3339))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// syncedAt returns when a user's list was last synced, or "" if never
+func (m *Mirror) syncedAt(ctx context.Context, user, kind string) (string, error) {
+	var syncedAt string
+	err := m.db.QueryRowContext(ctx, `SELECT synced_at FROM syncs WHERE user_name = ? AND type = ?`, user, kind).Scan(&syncedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read mirror: %w", err)
+	}
+	return syncedAt, nil
+}
+
+// updatedAts runs a query selecting id and updated_at pairs into a map
+func (m *Mirror) updatedAts(ctx context.Context, query string, args ...any) (map[int]string, error) {
+	rows, err := m.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mirror: %w", err)
+	}
+	defer rows.Close()
+	updated := make(map[int]string)
+	for rows.Next() {
+		var id int
+		var updatedAt string
+		if err := rows.Scan(&id, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to read mirror: %w", err)
+		}
+		updated[id] = updatedAt
+	}
+	return updated, rows.Err()
+}
+
+func (m *Mirror) setMeta(ctx context.Context, key, value string) error {
+	return m.write(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `INSERT INTO meta (key, value) VALUES (?, ?) ON CONFLICT (key) DO UPDATE SET value = excluded.value`, key, value)
+		return err
+	})
+}
+
+// write runs fn in a transaction
+func (m *Mirror) write(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to write mirror: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to write mirror: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to write mirror: %w", err)
+	}
+	return nil
+}
+
+// animeDetailFields requests every anime field except the logged-in user's
+// list status, which is mirrored with the list instead
+func animeDetailFields() []string {
+	fields := make([]string, 0, len(mal.AnimeFields))
+	for _, f := range mal.AnimeFields {
+		if f != "my_list_status" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+func idsOf[T any](items []T, id func(T) int) []int {
+	ids := make([]int, len(items))
+	for i, item := range items {
+		ids[i] = id(item)
+	}
+	return ids
+}
@@ -0,0 +1,129 @@
+package mirror
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+)
+
+// listServer serves one user's anime list and the details of the anime on
+// it, and counts how often details are fetched
+type listServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	anime   map[int]*mal.AnimeDetails
+	fetched int
+}
+
+func newListServer(t *testing.T, anime ...mal.AnimeDetails) *listServer {
+	t.Helper()
+	s := &listServer{anime: make(map[int]*mal.AnimeDetails)}
+	for i := range anime {
+		s.anime[anime[i].ID] = &anime[i]
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *listServer) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case r.URL.Path == "/users/spike/animelist":
+		list := mal.AnimeListResponse{Data: []mal.AnimeListEntry{}}
+		for _, anime := range s.anime {
+			list.Data = append(list.Data, mal.AnimeListEntry{
+				Node:       mal.AnimeDetails{ID: anime.ID, Title: anime.Title, UpdatedAt: anime.UpdatedAt},
+				ListStatus: mal.AnimeListStatus{Status: "watching", UpdatedAt: "2024-01-01T00:00:00+00:00"},
+			})
+		}
+		json.NewEncoder(w).Encode(list)
+	case strings.HasPrefix(r.URL.Path, "/anime/"):
+		id, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/anime/"))
+		anime, ok := s.anime[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		s.fetched++
+		json.NewEncoder(w).Encode(anime)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// update changes an anime's title as MAL would, bumping its updated_at
+func (s *listServer) update(id int, title, updatedAt string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.anime[id].Title = title
+	s.anime[id].UpdatedAt = updatedAt
+}
+
+// client returns a client for the server that caches responses on disk, as
+// zutto's does, with cache reads skipped like sync's
+func (s *listServer) client(t *testing.T) *mal.Client {
+	t.Helper()
+	client := mal.NewClient(s.Client(), "client-id")
+	if err := client.SetBaseURL(s.URL); err != nil {
+		t.Fatal(err)
+	}
+	client.SetRetryPolicy(mal.RetryPolicy{})
+	policy := mal.DefaultCachePolicy
+	policy.Refresh = true
+	client.SetCache(&mal.DiskCache{Dir: t.TempDir()}, policy)
+	return client
+}
+
+func TestSyncFetchesUpdatedDetails(t *testing.T) {
+	server := newListServer(t,
+		mal.AnimeDetails{ID: 1, Title: "Cowboy Bebop", UpdatedAt: "2024-01-01T00:00:00+00:00"},
+		mal.AnimeDetails{ID: 5, Title: "Cowboy Bebop: Tengoku no Tobira", UpdatedAt: "2024-01-01T00:00:00+00:00"},
+	)
+	client := server.client(t)
+	m := newTestMirror(t)
+	ctx := context.Background()
+
+	runSync := func(wantFetched int) {
+		t.Helper()
+		result, err := m.Sync(ctx, client, SyncOptions{User: "spike", Anime: true})
+		if err != nil {
+			t.Fatalf("Sync: %v", err)
+		}
+		if got := result.Anime.DetailsFetched; got != wantFetched {
+			t.Errorf("fetched %d details, want %d", got, wantFetched)
+		}
+	}
+
+	runSync(2)
+	// Nothing changed, so nothing is fetched again
+	runSync(0)
+
+	server.update(5, "Cowboy Bebop: The Movie", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339))
+	runSync(1)
+
+	anime, err := m.Anime.DetailsContext(ctx, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if anime.Title != "Cowboy Bebop: The Movie" {
+		t.Errorf("title = %q, want the updated title", anime.Title)
+	}
+	runSync(0)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.fetched != 3 {
+		t.Errorf("server served %d details, want 3", server.fetched)
+	}
+}
@@ -0,0 +1,87 @@
+package render
+
+import (
+	"io"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Bar is one row of a BarChart
+type Bar struct {
+	Label string
+	Value float64
+	// Note is printed after the bar, e.g. the value itself
+	Note string
+}
+
+// barEighths draw the fractional end of a bar in eighths of a cell
+var barEighths = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+// defaultChartWidth is used when the terminal width is unknown
+const defaultChartWidth = 80
+
+// BarChart draws horizontal bars scaled to the largest value
+type BarChart struct {
+	Bars []Bar
+	// Width is the terminal width to fit the chart in; 0 means 80 columns
+	Width int
+	// Color enables ANSI colors
+	Color bool
+}
+
+// NewBarChart returns an empty chart sized and colored for the terminal
+// described by width and color
+func NewBarChart(width int, color bool) *BarChart {
+	return &BarChart{Width: width, Color: color}
+}
+
+// Add appends a bar
+func (c *BarChart) Add(label string, value float64, note string) {
+	c.Bars = append(c.Bars, Bar{Label: label, Value: value, Note: note})
+}
+
+// Write draws the chart, one bar per line
+func (c *BarChart) Write(w io.Writer) error {
+	width := c.Width
+	if width <= 0 {
+		width = defaultChartWidth
+	}
+
+	var labelWidth, noteWidth int
+	var maxValue float64
+	for _, bar := range c.Bars {
+		labelWidth = max(labelWidth, runewidth.StringWidth(bar.Label))
+		noteWidth = max(noteWidth, runewidth.StringWidth(bar.Note))
+		maxValue = max(maxValue, bar.Value)
+	}
+	// Leave room for the label, the note and a space on either side of
+	// the bar, but keep bars visible on narrow terminals
+	barWidth := max(10, width-labelWidth-noteWidth-2)
+
+	var b strings.Builder
+	for _, bar := range c.Bars {
+		b.WriteString(runewidth.FillRight(bar.Label, labelWidth))
+		b.WriteByte(' ')
+
+		drawn := ""
+		if maxValue > 0 && bar.Value > 0 {
+			eighths := int(bar.Value / maxValue * float64(barWidth*8))
+			drawn = strings.Repeat("█", eighths/8) + barEighths[eighths%8]
+		}
+		if c.Color && drawn != "" {
+			b.WriteString("\x1b[36m" + drawn + "\x1b[0m")
+		} else {
+			b.WriteString(drawn)
+		}
+
+		if bar.Note != "" {
+			b.WriteString(strings.Repeat(" ", barWidth-runewidth.StringWidth(drawn)+1))
+			b.WriteString(bar.Note)
+		}
+		b.WriteByte('\n')
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,178 @@
+package render
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// writeRow writes v as a CSV/TSV row, writing the header first. Columns
+// default to every scalar field of v's type, named by dotted JSON path.
+func (r *Renderer) writeRow(v any) error {
+	if r.table == nil {
+		r.table = csv.NewWriter(r.w)
+		if r.opts.Format == TSV {
+			r.table.Comma = '\t'
+		}
+
+		available := Columns(v)
+		if len(r.opts.Columns) == 0 {
+			r.columns = available
+		} else {
+			known := make(map[string]bool, len(available))
+			for _, c := range available {
+				known[c] = true
+			}
+			for _, c := range r.opts.Columns {
+				if !known[c] {
+					return fmt.Errorf("unknown column %q, available columns: %s", c, strings.Join(available, ", "))
+				}
+			}
+			r.columns = r.opts.Columns
+		}
+		if err := r.table.Write(r.columns); err != nil {
+			return err
+		}
+	}
+
+	row := make([]string, len(r.columns))
+	value := reflect.ValueOf(v)
+	for i, column := range r.columns {
+		row[i] = cell(lookup(value, strings.Split(column, ".")))
+	}
+	return r.table.Write(row)
+}
+
+// Columns lists the dotted JSON paths of v's fields usable as CSV/TSV columns
+func Columns(v any) []string {
+	var columns []string
+	collectColumns(reflect.TypeOf(v), "", &columns)
+	return columns
+}
+
+func collectColumns(t reflect.Type, prefix string, columns *[]string) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		if prefix != "" {
+			*columns = append(*columns, prefix)
+		}
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := jsonName(field)
+		if name == "" {
+			continue
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		ft := field.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		// Lists are flattened into a single cell rather than expanded
+		if ft.Kind() == reflect.Struct {
+			collectColumns(ft, name, columns)
+		} else {
+			*columns = append(*columns, name)
+		}
+	}
+}
+
+// jsonName returns the JSON key of an exported field, or "" if it is skipped
+func jsonName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name
+}
+
+// lookup follows a dotted JSON path from v, returning an invalid Value if a
+// pointer along the way is nil
+func lookup(v reflect.Value, path []string) reflect.Value {
+	for _, name := range path {
+		v = indirect(v)
+		if !v.IsValid() || v.Kind() != reflect.Struct {
+			return reflect.Value{}
+		}
+		t := v.Type()
+		next := reflect.Value{}
+		for i := 0; i < t.NumField(); i++ {
+			if jsonName(t.Field(i)) == name {
+				next = v.Field(i)
+				break
+			}
+		}
+		v = next
+	}
+	return indirect(v)
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// cell formats a value for a CSV cell. Lists of scalars are joined with ";",
+// lists of named things (genres, studios) by name, anything else as JSON.
+func cell(v reflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Slice, reflect.Array:
+		parts := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			item := indirect(v.Index(i))
+			if item.Kind() == reflect.Struct {
+				name := lookup(item, []string{"name"})
+				if !name.IsValid() {
+					return jsonCell(v)
+				}
+				item = name
+			}
+			parts = append(parts, cell(item))
+		}
+		return strings.Join(parts, ";")
+	}
+	return jsonCell(v)
+}
+
+func jsonCell(v reflect.Value) string {
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
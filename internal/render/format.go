@@ -0,0 +1,25 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatStatus turns a MAL status like "plan_to_watch" into "plan to watch"
+func FormatStatus(status string) string {
+	return strings.ReplaceAll(status, "_", " ")
+}
+
+// FormatProgress renders watched/total, using "?" when the total is unknown
+func FormatProgress(watched, total int) string {
+	return fmt.Sprintf("%d/%s", watched, CountOrUnknown(total))
+}
+
+// CountOrUnknown renders a count MAL reports as 0 when it is not known yet,
+// like the episodes of an airing anime, as "?"
+func CountOrUnknown(n int) string {
+	if n == 0 {
+		return "?"
+	}
+	return fmt.Sprint(n)
+}
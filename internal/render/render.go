@@ -0,0 +1,235 @@
+// Package render writes command results as text, JSON, JSON Lines, YAML,
+// CSV, TSV or a user-supplied Go template.
+//
+// Structured formats use the JSON field names of the values passed in, so
+// output from the mal types matches the MyAnimeList API. Commands keep
+// printing their own human-readable text; Renderer.Text reports when they
+// should.
+package render
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"go.yaml.in/yaml/v3"
+)
+
+type Format string
+
+const (
+	Text  Format = "text"
+	JSON  Format = "json"
+	JSONL Format = "jsonl"
+	YAML  Format = "yaml"
+	CSV   Format = "csv"
+	TSV   Format = "tsv"
+)
+
+// Formats lists every supported output format
+var Formats = []Format{Text, JSON, JSONL, YAML, CSV, TSV}
+
+func ValidateFormat(format string) error {
+	for _, f := range Formats {
+		if string(f) == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid output format: %s", format)
+}
+
+// Options selects how results are rendered
+type Options struct {
+	Format Format
+	// Columns picks CSV/TSV columns by dotted JSON path, e.g. node.title.
+	// Commands that print tables also use it to pick table columns.
+	Columns []string
+	// Template is a Go text/template executed once per record against its
+	// JSON form, e.g. {{.node.id}}: {{.node.title}}. It overrides Format.
+	Template string
+}
+
+// Renderer writes records in the selected format. Call Item once per record
+// of a list result, or Value for a single result, then Flush.
+type Renderer struct {
+	w    io.Writer
+	opts Options
+	tmpl *template.Template
+
+	items   []any
+	single  bool
+	table   *csv.Writer
+	columns []string
+}
+
+func New(w io.Writer, opts Options) (*Renderer, error) {
+	if opts.Format == "" {
+		opts.Format = Text
+	}
+	if err := ValidateFormat(string(opts.Format)); err != nil {
+		return nil, err
+	}
+	if len(opts.Columns) > 0 && (opts.Template != "" || opts.Format != Text && opts.Format != CSV && opts.Format != TSV) {
+		return nil, fmt.Errorf("columns can only be used with text, csv or tsv output")
+	}
+
+	r := &Renderer{w: w, opts: opts}
+	if opts.Template != "" {
+		tmpl, err := template.New("output").Funcs(templateFuncs).Parse(opts.Template)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template: %w", err)
+		}
+		r.tmpl = tmpl
+	}
+	return r, nil
+}
+
+// Text reports whether the caller should print its own human-readable output
+func (r *Renderer) Text() bool {
+	return r.opts.Format == Text && r.tmpl == nil
+}
+
+// Item renders one record of a list result
+func (r *Renderer) Item(v any) error {
+	switch {
+	case r.tmpl != nil:
+		return r.execute(v)
+	case r.opts.Format == JSON || r.opts.Format == YAML:
+		// Arrays are written as a whole by Flush
+		r.items = append(r.items, v)
+		return nil
+	case r.opts.Format == JSONL:
+		return r.writeJSONLine(v)
+	case r.opts.Format == CSV || r.opts.Format == TSV:
+		return r.writeRow(v)
+	}
+	return nil
+}
+
+// Value renders a result that is a single object rather than a list
+func (r *Renderer) Value(v any) error {
+	r.single = true
+	switch {
+	case r.tmpl != nil:
+		return r.execute(v)
+	case r.opts.Format == JSON:
+		enc := json.NewEncoder(r.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case r.opts.Format == YAML:
+		return r.writeYAML(v)
+	}
+	return r.Item(v)
+}
+
+// Flush writes buffered records. List results with no records still produce
+// an empty JSON array or YAML sequence.
+func (r *Renderer) Flush() error {
+	switch {
+	case r.tmpl != nil:
+		return nil
+	case r.opts.Format == JSON && !r.single:
+		items := r.items
+		if items == nil {
+			items = []any{}
+		}
+		enc := json.NewEncoder(r.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+	case r.opts.Format == YAML && !r.single:
+		if r.items == nil {
+			_, err := fmt.Fprintln(r.w, "[]")
+			return err
+		}
+		return r.writeYAML(r.items)
+	case r.table != nil:
+		r.table.Flush()
+		return r.table.Error()
+	}
+	return nil
+}
+
+func (r *Renderer) writeJSONLine(v any) error {
+	return json.NewEncoder(r.w).Encode(v)
+}
+
+func (r *Renderer) execute(v any) error {
+	data, err := toJSONValue(v)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := r.tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	_, err = r.w.Write(buf.Bytes())
+	return err
+}
+
+// writeYAML converts v through JSON so keys keep their JSON names and order
+func (r *Renderer) writeYAML(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	clearStyle(&node)
+
+	enc := yaml.NewEncoder(r.w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	return enc.Close()
+}
+
+// clearStyle drops the flow and quoting styles inherited from the JSON
+// source so the encoder emits block-style YAML
+func clearStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearStyle(child)
+	}
+}
+
+// toJSONValue round-trips v through JSON so templates see JSON field names
+func toJSONValue(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode output: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var out any
+	if err := dec.Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to encode output: %w", err)
+	}
+	return out, nil
+}
+
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	// default returns def when v is missing or empty
+	"default": func(def, v any) any {
+		if v == nil || v == "" {
+			return def
+		}
+		return v
+	},
+}
@@ -0,0 +1,194 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Align controls how a column's cells are padded
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignRight
+)
+
+// Column describes one column of a Table
+type Column struct {
+	// Name selects the column with --columns
+	Name   string
+	Header string
+	Align  Align
+	// Flex columns are truncated when the table is wider than the terminal
+	Flex bool
+	// Color returns the ANSI SGR parameters for a cell (e.g. "32" for
+	// green), or "" to leave it uncolored
+	Color func(cell string) string
+}
+
+// minFlexWidth is the narrowest a flex column is truncated to
+const minFlexWidth = 8
+
+// Table lays out rows in aligned columns, measuring East Asian wide
+// characters as two cells
+type Table struct {
+	Columns []Column
+	// Width is the terminal width to fit the table in; 0 means unlimited
+	Width int
+	// Color enables ANSI colors
+	Color bool
+
+	rows    [][]string
+	visible []int
+}
+
+// NewTable returns a table with the given columns, sized and colored for
+// the terminal described by width and color
+func NewTable(width int, color bool, columns ...Column) *Table {
+	return &Table{Columns: columns, Width: width, Color: color}
+}
+
+// Select shows only the named columns, in the given order. With no names,
+// every column is shown.
+func (t *Table) Select(names []string) error {
+	if len(names) == 0 {
+		t.visible = nil
+		return nil
+	}
+
+	visible := make([]int, 0, len(names))
+	for _, name := range names {
+		index := -1
+		for i, c := range t.Columns {
+			if c.Name == name {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return fmt.Errorf("unknown column %q, available columns: %s", name, strings.Join(t.ColumnNames(), ", "))
+		}
+		visible = append(visible, index)
+	}
+	t.visible = visible
+	return nil
+}
+
+// ColumnNames lists the names accepted by Select
+func (t *Table) ColumnNames() []string {
+	names := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// Append adds a row with one cell per column, in Columns order
+func (t *Table) Append(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// Len returns the number of rows
+func (t *Table) Len() int {
+	return len(t.rows)
+}
+
+// Write lays out the table and writes it to w
+func (t *Table) Write(w io.Writer) error {
+	columns := t.visible
+	if columns == nil {
+		columns = make([]int, len(t.Columns))
+		for i := range columns {
+			columns[i] = i
+		}
+	}
+
+	widths := t.layout(columns)
+	var b strings.Builder
+	for i, c := range columns {
+		if i > 0 {
+			b.WriteString("  ")
+		}
+		t.writeCell(&b, t.Columns[c].Header, t.Columns[c], widths[i], i == len(columns)-1, "1")
+	}
+	b.WriteString("\n")
+
+	for _, row := range t.rows {
+		for i, c := range columns {
+			if i > 0 {
+				b.WriteString("  ")
+			}
+			var cell string
+			if c < len(row) {
+				cell = row[c]
+			}
+			var color string
+			if t.Columns[c].Color != nil {
+				color = t.Columns[c].Color(cell)
+			}
+			t.writeCell(&b, cell, t.Columns[c], widths[i], i == len(columns)-1, color)
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// layout returns the display width of each visible column. Flex columns are
+// narrowed, widest first, until the table fits Width.
+func (t *Table) layout(columns []int) []int {
+	widths := make([]int, len(columns))
+	for i, c := range columns {
+		widths[i] = runewidth.StringWidth(t.Columns[c].Header)
+		for _, row := range t.rows {
+			if c < len(row) {
+				widths[i] = max(widths[i], runewidth.StringWidth(row[c]))
+			}
+		}
+	}
+	if t.Width <= 0 {
+		return widths
+	}
+
+	total := 2 * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	for total > t.Width {
+		widest := -1
+		for i, c := range columns {
+			if t.Columns[c].Flex && widths[i] > minFlexWidth && (widest < 0 || widths[i] > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		widths[widest]--
+		total--
+	}
+	return widths
+}
+
+func (t *Table) writeCell(b *strings.Builder, cell string, column Column, width int, last bool, color string) {
+	if runewidth.StringWidth(cell) > width {
+		cell = runewidth.Truncate(cell, width, "…")
+	}
+	pad := width - runewidth.StringWidth(cell)
+	if column.Align == AlignRight {
+		b.WriteString(strings.Repeat(" ", pad))
+	}
+	if t.Color && color != "" && cell != "" {
+		b.WriteString("\x1b[" + color + "m" + cell + "\x1b[0m")
+	} else {
+		b.WriteString(cell)
+	}
+	// Trailing padding on the last column would only wrap narrow terminals
+	if column.Align == AlignLeft && !last {
+		b.WriteString(strings.Repeat(" ", pad))
+	}
+}
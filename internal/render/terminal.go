@@ -0,0 +1,37 @@
+package render
+
+import (
+	"os"
+	"strconv"
+
+	"golang.org/x/term"
+)
+
+// TerminalWidth returns the width of f in cells, or 0 if f is not a
+// terminal. COLUMNS overrides the detected width.
+func TerminalWidth(f *os.File) int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	if !term.IsTerminal(int(f.Fd())) {
+		return 0
+	}
+	width, _, err := term.GetSize(int(f.Fd()))
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+// ColorEnabled reports whether ANSI colors should be written to f. Colors
+// are off when NO_COLOR is set (https://no-color.org), TERM is "dumb", or f
+// is not a terminal.
+func ColorEnabled(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return term.IsTerminal(int(f.Fd()))
+}
@@ -0,0 +1,402 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+	"github.com/bradleyyma/zutto/internal/render"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// detailFields are requested for the detail pane
+var detailFields = []string{
+	"id", "title", "alternative_titles", "synopsis", "mean", "rank", "popularity",
+	"num_episodes", "status", "media_type", "start_date", "end_date", "genres",
+	"studios", "my_list_status",
+}
+
+// listFields are requested for result lists
+var listFields = []string{"alternative_titles", "mean", "num_episodes", "media_type"}
+
+const (
+	pageSize = 50
+	// chromeHeight is the number of lines around the result list: tabs,
+	// search box, borders and the status line
+	chromeHeight = 6
+)
+
+type tabKind int
+
+const (
+	searchTab tabKind = iota
+	rankingTab
+	watchingTab
+)
+
+// item is one row of a result list
+type item struct {
+	anime mal.AnimeDetails
+	// rank is the ranking position, 0 outside ranking tabs
+	rank int
+}
+
+type tab struct {
+	name        string
+	kind        tabKind
+	rankingType string
+
+	items   []item
+	cursor  int
+	scroll  int
+	loading bool
+	loaded  bool
+	err     error
+}
+
+// Model is the TUI's bubbletea model
+type Model struct {
+	ctx    context.Context
+	client *mal.Client
+	lang   mal.TitleLanguage
+
+	tabs   []*tab
+	active int
+	search textinput.Model
+
+	// details caches the detail pane by anime ID; pending marks requests
+	// in flight
+	details map[int]*mal.AnimeDetails
+	pending map[int]bool
+
+	status string
+	width  int
+	height int
+}
+
+// Messages sent back by commands
+type (
+	resultsMsg struct {
+		tab   int
+		items []item
+		err   error
+	}
+	detailsMsg struct {
+		id      int
+		details *mal.AnimeDetails
+		err     error
+	}
+	listUpdatedMsg struct {
+		id     int
+		status *mal.AnimeListStatus
+		text   string
+		err    error
+	}
+)
+
+// New returns a model that makes its requests with client under ctx
+func New(ctx context.Context, client *mal.Client, opts Options) Model {
+	search := textinput.New()
+	search.Placeholder = "search anime"
+	search.Prompt = "Search: "
+	search.SetValue(opts.Query)
+	if opts.Query == "" {
+		search.Focus()
+	}
+
+	return Model{
+		ctx:    ctx,
+		client: client,
+		lang:   opts.TitleLanguage,
+		tabs: []*tab{
+			{name: "Search", kind: searchTab},
+			{name: "Top", kind: rankingTab, rankingType: "all"},
+			{name: "Airing", kind: rankingTab, rankingType: "airing"},
+			{name: "TV", kind: rankingTab, rankingType: "tv"},
+			{name: "Movies", kind: rankingTab, rankingType: "movie"},
+			{name: "Popular", kind: rankingTab, rankingType: "bypopularity"},
+			{name: "Favorites", kind: rankingTab, rankingType: "favorite"},
+			{name: "Watching", kind: watchingTab},
+		},
+		search:  search,
+		details: make(map[int]*mal.AnimeDetails),
+		pending: make(map[int]bool),
+		status:  helpText,
+	}
+}
+
+const helpText = "/ search  tab switch  ↑↓ move  a add to list  + watched episode  r reload  q quit"
+
+func (m Model) Init() tea.Cmd {
+	if m.search.Value() != "" {
+		return m.load(0)
+	}
+	return textinput.Blink
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.search.Width = max(10, msg.Width-len(m.search.Prompt)-2)
+		return m, nil
+
+	case resultsMsg:
+		t := m.tabs[msg.tab]
+		t.loading = false
+		t.loaded = true
+		t.err = msg.err
+		if msg.err == nil {
+			t.items = msg.items
+			t.cursor, t.scroll = 0, 0
+		}
+		if msg.tab == m.active {
+			return m, m.loadSelected()
+		}
+		return m, nil
+
+	case detailsMsg:
+		delete(m.pending, msg.id)
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error loading anime %d: %v", msg.id, msg.err)
+			return m, nil
+		}
+		m.details[msg.id] = msg.details
+		return m, nil
+
+	case listUpdatedMsg:
+		if msg.err != nil {
+			m.status = "Error: " + msg.err.Error()
+			return m, nil
+		}
+		if details, ok := m.details[msg.id]; ok && msg.status != nil {
+			details.MyListStatus = msg.status
+		}
+		m.status = msg.text
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.search.Focused() {
+			return m.updateSearch(msg)
+		}
+		return m.updateKeys(msg)
+	}
+	return m, nil
+}
+
+// updateSearch handles keys while the search box has focus
+func (m Model) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.search.Blur()
+		return m, nil
+	case tea.KeyTab, tea.KeyShiftTab:
+		m.search.Blur()
+		return m.updateKeys(msg)
+	case tea.KeyEnter:
+		m.search.Blur()
+		if strings.TrimSpace(m.search.Value()) == "" {
+			return m, nil
+		}
+		return m, m.load(0)
+	}
+
+	var cmd tea.Cmd
+	m.search, cmd = m.search.Update(msg)
+	return m, cmd
+}
+
+// updateKeys handles keys while browsing a list
+func (m Model) updateKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	t := m.tabs[m.active]
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "/":
+		m.active = 0
+		m.search.Focus()
+		return m, textinput.Blink
+	case "tab", "right", "l":
+		return m.switchTab((m.active + 1) % len(m.tabs))
+	case "shift+tab", "left", "h":
+		return m.switchTab((m.active + len(m.tabs) - 1) % len(m.tabs))
+	case "up", "k":
+		m.moveCursor(t, -1)
+		return m, m.loadSelected()
+	case "down", "j":
+		m.moveCursor(t, 1)
+		return m, m.loadSelected()
+	case "pgup":
+		m.moveCursor(t, -m.listHeight())
+		return m, m.loadSelected()
+	case "pgdown":
+		m.moveCursor(t, m.listHeight())
+		return m, m.loadSelected()
+	case "r":
+		if t.kind == searchTab && strings.TrimSpace(m.search.Value()) == "" {
+			return m, nil
+		}
+		return m, m.load(m.active)
+	case "a":
+		if selected := m.selected(); selected != nil {
+			return m, m.addToList(selected.anime.ID, m.title(&selected.anime))
+		}
+	case "+", "w":
+		if selected := m.selected(); selected != nil {
+			return m, m.watch(selected.anime.ID)
+		}
+	}
+	return m, nil
+}
+
+func (m Model) switchTab(index int) (tea.Model, tea.Cmd) {
+	m.active = index
+	t := m.tabs[index]
+	if !t.loaded && !t.loading && t.kind != searchTab {
+		return m, m.load(index)
+	}
+	return m, m.loadSelected()
+}
+
+func (m *Model) moveCursor(t *tab, delta int) {
+	if len(t.items) == 0 {
+		return
+	}
+	t.cursor = min(max(t.cursor+delta, 0), len(t.items)-1)
+
+	height := m.listHeight()
+	if t.cursor < t.scroll {
+		t.scroll = t.cursor
+	} else if t.cursor >= t.scroll+height {
+		t.scroll = t.cursor - height + 1
+	}
+}
+
+// listHeight is the number of result rows that fit on screen
+func (m Model) listHeight() int {
+	return max(1, m.height-chromeHeight)
+}
+
+func (m Model) selected() *item {
+	t := m.tabs[m.active]
+	if t.cursor >= len(t.items) {
+		return nil
+	}
+	return &t.items[t.cursor]
+}
+
+func (m Model) title(anime *mal.AnimeDetails) string {
+	return anime.PreferredTitle(m.lang)
+}
+
+// load fetches the contents of a tab
+func (m Model) load(index int) tea.Cmd {
+	t := m.tabs[index]
+	t.loading = true
+	t.err = nil
+	ctx, client, query := m.ctx, m.client, strings.TrimSpace(m.search.Value())
+	kind, rankingType := t.kind, t.rankingType
+
+	return func() tea.Msg {
+		var items []item
+		switch kind {
+		case searchTab:
+			results, err := client.Anime.SearchContext(ctx, query, pageSize, listFields...)
+			if err != nil {
+				return resultsMsg{tab: index, err: err}
+			}
+			for _, r := range results.Data {
+				items = append(items, item{anime: r.Node})
+			}
+		case rankingTab:
+			rankings, err := client.Anime.RankingsContext(ctx, rankingType, pageSize, 0, listFields...)
+			if err != nil {
+				return resultsMsg{tab: index, err: err}
+			}
+			for _, r := range rankings.Data {
+				items = append(items, item{anime: r.Node, rank: r.Ranking.Rank})
+			}
+		case watchingTab:
+			list, err := client.List.AnimeListContext(ctx, "@me", mal.AnimeListOptions{
+				Status: "watching",
+				Sort:   "list_updated_at",
+				Limit:  pageSize,
+			})
+			if err != nil {
+				return resultsMsg{tab: index, err: err}
+			}
+			for _, entry := range list.Data {
+				anime := entry.Node
+				status := entry.ListStatus
+				anime.MyListStatus = &status
+				items = append(items, item{anime: anime})
+			}
+		}
+		return resultsMsg{tab: index, items: items}
+	}
+}
+
+// loadSelected fetches details for the selected anime unless they are
+// cached or already being fetched
+func (m Model) loadSelected() tea.Cmd {
+	selected := m.selected()
+	if selected == nil {
+		return nil
+	}
+	id := selected.anime.ID
+	if m.details[id] != nil || m.pending[id] {
+		return nil
+	}
+	m.pending[id] = true
+
+	ctx, client := m.ctx, m.client
+	return func() tea.Msg {
+		details, err := client.Anime.DetailsContext(ctx, id, detailFields...)
+		return detailsMsg{id: id, details: details, err: err}
+	}
+}
+
+// addToList adds an anime to the user's plan to watch list unless it is
+// already listed
+func (m Model) addToList(id int, title string) tea.Cmd {
+	ctx, client := m.ctx, m.client
+	return func() tea.Msg {
+		current, err := client.List.AnimeStatusContext(ctx, id)
+		if err != nil {
+			return listUpdatedMsg{id: id, err: err}
+		}
+		if current != nil {
+			return listUpdatedMsg{id: id, status: current, text: fmt.Sprintf("%s is already on your list (%s)", title, render.FormatStatus(current.Status))}
+		}
+
+		status := "plan_to_watch"
+		updated, err := client.List.UpdateAnimeContext(ctx, id, mal.AnimeListUpdate{Status: &status})
+		if err != nil {
+			return listUpdatedMsg{id: id, err: err}
+		}
+		return listUpdatedMsg{id: id, status: updated, text: fmt.Sprintf("Added %s to plan to watch", title)}
+	}
+}
+
+// watch records the next episode of an anime as watched
+func (m Model) watch(id int) tea.Cmd {
+	ctx, client, lang := m.ctx, m.client, m.lang
+	return func() tea.Msg {
+		result, err := client.List.WatchContext(ctx, id, mal.WatchOptions{})
+		if errors.Is(err, mal.ErrAlreadyCompleted) {
+			return listUpdatedMsg{id: id, err: fmt.Errorf("already completed; use `zutto watch %d --rewatch` to record a rewatch", id)}
+		}
+		if err != nil {
+			return listUpdatedMsg{id: id, err: err}
+		}
+
+		text := fmt.Sprintf("%s: watched %s episodes (%s)", result.PreferredTitle(lang),
+			render.FormatProgress(result.Current.NumEpisodesWatched, result.NumEpisodes), render.FormatStatus(result.Current.Status))
+		return listUpdatedMsg{id: id, status: result.Current, text: text}
+	}
+}
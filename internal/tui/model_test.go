@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fakeMAL serves the endpoints the TUI uses and records list updates
+type fakeMAL struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	rankingTypes []string
+	updates      []string
+}
+
+func newFakeMAL(t *testing.T) *fakeMAL {
+	t.Helper()
+	f := &fakeMAL{}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeMAL) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case r.URL.Path == "/anime":
+		w.Write([]byte(`{"data":[
+			{"node":{"id":1,"title":"Sousou no Frieren","num_episodes":28,"media_type":"tv","mean":9.3}},
+			{"node":{"id":2,"title":"Steins;Gate","num_episodes":24,"media_type":"tv","mean":9.07}}
+		],"paging":{}}`))
+	case r.URL.Path == "/anime/ranking":
+		f.rankingTypes = append(f.rankingTypes, r.URL.Query().Get("ranking_type"))
+		w.Write([]byte(`{"data":[
+			{"node":{"id":3,"title":"Kusuriya no Hitorigoto","num_episodes":24,"media_type":"tv"},"ranking":{"rank":1}}
+		],"paging":{}}`))
+	case r.Method == http.MethodPatch && r.URL.Path == "/anime/1/my_list_status":
+		r.ParseForm()
+		f.updates = append(f.updates, r.PostForm.Encode())
+		w.Write([]byte(`{"status":"plan_to_watch","num_episodes_watched":0}`))
+	case r.URL.Query().Get("fields") == "my_list_status":
+		// Nothing is on the list yet
+		w.Write([]byte(`{"id":1,"title":"Sousou no Frieren"}`))
+	case r.URL.Path == "/anime/1":
+		w.Write([]byte(`{"id":1,"title":"Sousou no Frieren","synopsis":"An elf mage outlives her party.","num_episodes":28}`))
+	case r.URL.Path == "/anime/2":
+		w.Write([]byte(`{"id":2,"title":"Steins;Gate","synopsis":"A self-proclaimed mad scientist.","num_episodes":24}`))
+	case r.URL.Path == "/anime/3":
+		w.Write([]byte(`{"id":3,"title":"Kusuriya no Hitorigoto","synopsis":"An apothecary in the inner court."}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// update sends msg to m, then runs each command it returns and feeds the
+// result back, the way the bubbletea runtime would, until none is left
+func update(m tea.Model, msg tea.Msg) tea.Model {
+	for msg != nil {
+		var cmd tea.Cmd
+		m, cmd = m.Update(msg)
+		if cmd == nil {
+			break
+		}
+		msg = cmd()
+	}
+	return m
+}
+
+func key(s string) tea.KeyMsg {
+	switch s {
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func newTestModel(t *testing.T, query string) (*fakeMAL, tea.Model) {
+	t.Helper()
+	server := newFakeMAL(t)
+	client := mal.NewClient(server.Client(), "client-id")
+	if err := client.SetBaseURL(server.URL); err != nil {
+		t.Fatal(err)
+	}
+	client.SetRetryPolicy(mal.RetryPolicy{})
+
+	m := New(context.Background(), client, Options{Query: query})
+	var model tea.Model = m
+	model = update(model, tea.WindowSizeMsg{Width: 120, Height: 20})
+	model = update(model, m.Init()())
+	return server, model
+}
+
+func TestSearchAndDetails(t *testing.T) {
+	_, m := newTestModel(t, "frieren")
+
+	view := m.View()
+	for _, want := range []string{"Sousou no Frieren", "Steins;Gate", "An elf mage outlives her party."} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view is missing %q:\n%s", want, view)
+		}
+	}
+
+	m = update(m, key("down"))
+	if view := m.View(); !strings.Contains(view, "A self-proclaimed mad scientist.") {
+		t.Errorf("moving down did not show the second anime's details:\n%s", view)
+	}
+}
+
+func TestRankingTabs(t *testing.T) {
+	server, m := newTestModel(t, "frieren")
+
+	m = update(m, key("tab"))
+	m = update(m, key("tab"))
+	if view := m.View(); !strings.Contains(view, "Kusuriya no Hitorigoto") {
+		t.Errorf("airing tab does not show its ranking:\n%s", view)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	want := []string{"all", "airing"}
+	if strings.Join(server.rankingTypes, ",") != strings.Join(want, ",") {
+		t.Errorf("requested ranking types %q, want %q", server.rankingTypes, want)
+	}
+	for _, rankingType := range server.rankingTypes {
+		if err := mal.ValidateAnimeRankingType(rankingType); err != nil {
+			t.Errorf("tab uses a ranking type MAL does not accept: %v", err)
+		}
+	}
+}
+
+func TestAddToList(t *testing.T) {
+	server, m := newTestModel(t, "frieren")
+
+	m = update(m, key("a"))
+	if view := m.View(); !strings.Contains(view, "Added Sousou no Frieren to plan to watch") {
+		t.Errorf("status line does not confirm the add:\n%s", view)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.updates) != 1 || server.updates[0] != "status=plan_to_watch" {
+		t.Errorf("list updates = %q, want one setting plan_to_watch", server.updates)
+	}
+}
@@ -0,0 +1,32 @@
+// Package tui implements `zutto tui`, a full-screen browser for anime
+// search, rankings and the user's list.
+//
+// Model is a plain bubbletea model: every MAL request goes through the
+// mal.Client it is given, so it can be driven headlessly (by sending
+// messages to Update and reading View) against a client pointed at a fake
+// server with SetBaseURL.
+package tui
+
+import (
+	"context"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Options configures the TUI
+type Options struct {
+	// TitleLanguage selects which title is shown for each anime
+	TitleLanguage mal.TitleLanguage
+	// Query, if set, is searched for on start
+	Query string
+}
+
+// Run starts the TUI and blocks until the user quits or ctx is canceled.
+// Extra program options can redirect input and output, e.g. for tests.
+func Run(ctx context.Context, client *mal.Client, opts Options, programOpts ...tea.ProgramOption) error {
+	programOpts = append([]tea.ProgramOption{tea.WithAltScreen(), tea.WithContext(ctx)}, programOpts...)
+	program := tea.NewProgram(New(ctx, client, opts), programOpts...)
+	_, err := program.Run()
+	return err
+}
@@ -0,0 +1,189 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bradleyyma/zutto/internal/mal"
+	"github.com/bradleyyma/zutto/internal/render"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+var (
+	activeTabStyle   = lipgloss.NewStyle().Bold(true).Reverse(true).Padding(0, 1)
+	inactiveTabStyle = lipgloss.NewStyle().Padding(0, 1)
+	paneStyle        = lipgloss.NewStyle().Border(lipgloss.RoundedBorder())
+	selectedStyle    = lipgloss.NewStyle().Reverse(true)
+	titleStyle       = lipgloss.NewStyle().Bold(true)
+	labelStyle       = lipgloss.NewStyle().Faint(true)
+	statusStyle      = lipgloss.NewStyle().Faint(true)
+)
+
+// View draws the tabs, the search box on the search tab, the result list
+// beside the detail pane, and the status line
+func (m Model) View() string {
+	if m.width == 0 || m.height == 0 {
+		return "Loading…"
+	}
+
+	var tabs []string
+	for i, t := range m.tabs {
+		style := inactiveTabStyle
+		if i == m.active {
+			style = activeTabStyle
+		}
+		tabs = append(tabs, style.Render(t.name))
+	}
+
+	t := m.tabs[m.active]
+	searchLine := ""
+	if t.kind == searchTab {
+		searchLine = m.search.View()
+	}
+
+	// Panes share the width; each border takes two columns
+	listWidth := max(20, m.width*2/5) - 2
+	detailWidth := max(20, m.width-listWidth-4)
+	height := m.listHeight()
+
+	list := paneStyle.Width(listWidth).Height(height).Render(m.listView(t, listWidth, height))
+	detail := paneStyle.Width(detailWidth).Height(height).Render(m.detailView(detailWidth, height))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, tabs...),
+		searchLine,
+		lipgloss.JoinHorizontal(lipgloss.Top, list, detail),
+		statusStyle.Render(runewidth.Truncate(m.status, m.width, "…")),
+	)
+}
+
+// listView draws the visible rows of a tab's results
+func (m Model) listView(t *tab, width, height int) string {
+	switch {
+	case t.loading:
+		return "Loading…"
+	case t.err != nil:
+		return wrap("Error: "+t.err.Error(), width)
+	case t.kind == searchTab && !t.loaded:
+		return "Type a title and press enter"
+	case len(t.items) == 0:
+		return "Nothing found"
+	}
+
+	var lines []string
+	for i := t.scroll; i < len(t.items) && i < t.scroll+height; i++ {
+		line := m.formatRow(&t.items[i], width)
+		if i == t.cursor {
+			line = selectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatRow lays out one result as "rank title score", padded to width
+func (m Model) formatRow(it *item, width int) string {
+	var prefix string
+	if it.rank > 0 {
+		prefix = fmt.Sprintf("%3d. ", it.rank)
+	}
+
+	var suffix string
+	if status := it.anime.MyListStatus; status != nil {
+		suffix = " " + render.FormatProgress(status.NumEpisodesWatched, it.anime.NumEpisodes)
+	} else if it.anime.Mean > 0 {
+		suffix = fmt.Sprintf(" %.2f", it.anime.Mean)
+	}
+
+	titleWidth := max(1, width-runewidth.StringWidth(prefix)-runewidth.StringWidth(suffix))
+	title := runewidth.FillRight(runewidth.Truncate(m.title(&it.anime), titleWidth, "…"), titleWidth)
+	return prefix + title + suffix
+}
+
+// detailView draws the selected anime's details, falling back to what the
+// result list already knows while they load
+func (m Model) detailView(width, height int) string {
+	selected := m.selected()
+	if selected == nil {
+		return ""
+	}
+	anime := m.details[selected.anime.ID]
+	if anime == nil {
+		anime = &selected.anime
+	}
+
+	lines := []string{titleStyle.Render(wrap(m.title(anime), width))}
+	if anime.Title != m.title(anime) {
+		lines = append(lines, wrap(anime.Title, width))
+	}
+	if en := anime.AlternativeTitles.En; en != "" && en != m.title(anime) {
+		lines = append(lines, wrap(en, width))
+	}
+	lines = append(lines, "")
+
+	field := func(label, value string) {
+		if value != "" {
+			lines = append(lines, labelStyle.Render(label+": ")+value)
+		}
+	}
+	field("ID", fmt.Sprint(anime.ID))
+	field("Type", anime.MediaType)
+	if anime.NumEpisodes > 0 || anime.MediaType != "" {
+		field("Episodes", render.CountOrUnknown(anime.NumEpisodes))
+	}
+	field("Status", render.FormatStatus(anime.Status))
+	if anime.Mean > 0 {
+		field("Score", fmt.Sprintf("%.2f", anime.Mean))
+	}
+	if anime.Rank > 0 {
+		field("Rank", fmt.Sprintf("#%d", anime.Rank))
+	}
+	field("Aired", formatDates(anime.StartDate, anime.EndDate))
+	field("Genres", joinNames(anime.Genres, func(g mal.Genre) string { return g.Name }))
+	field("Studios", joinNames(anime.Studios, func(s mal.Studio) string { return s.Name }))
+
+	if status := anime.MyListStatus; status != nil {
+		list := fmt.Sprintf("%s, %s episodes", render.FormatStatus(status.Status), render.FormatProgress(status.NumEpisodesWatched, anime.NumEpisodes))
+		if status.Score > 0 {
+			list += fmt.Sprintf(", scored %d", status.Score)
+		}
+		field("My list", list)
+	}
+
+	if m.pending[anime.ID] {
+		lines = append(lines, "", "Loading…")
+	} else if anime.Synopsis != "" {
+		lines = append(lines, "", wrap(anime.Synopsis, width))
+	}
+
+	// Keep the pane at its height even for long synopses
+	text := strings.Split(strings.Join(lines, "\n"), "\n")
+	if len(text) > height {
+		text = append(text[:height-1], "…")
+	}
+	return strings.Join(text, "\n")
+}
+
+// wrap breaks text into lines of at most width cells
+func wrap(text string, width int) string {
+	return lipgloss.NewStyle().Width(width).Render(text)
+}
+
+func formatDates(start, end string) string {
+	switch {
+	case start == "":
+		return ""
+	case end == "" || end == start:
+		return start
+	}
+	return start + " to " + end
+}
+
+func joinNames[T any](items []T, name func(T) string) string {
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = name(item)
+	}
+	return strings.Join(names, ", ")
+}